@@ -0,0 +1,82 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/bench"
+	"goful/core/client"
+	"goful/core/client/builder"
+	"goful/core/print"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+)
+
+type BenchFlags struct {
+	Plain       bool
+	Concurrency int
+	Requests    int
+	Rate        string
+}
+
+var benchFlags BenchFlags
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [NAME]",
+	Short: "Load test a request of the workspace",
+	Long: `Send a request of the workspace repeatedly and report throughput, error rate, status codes and latency percentiles.
+The request is built once with the active profile and sent by concurrent workers sharing their connections.
+Requests that receive no response or a 4xx or 5xx status count as errors. Interrupting prints the results so far.`,
+	Example: `goful bench "Get users" -c 20 -n 1000 --rate 50/s`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rate, err := bench.ParseRate(benchFlags.Rate)
+		if err != nil {
+			return err
+		}
+		if benchFlags.Requests < 1 || benchFlags.Concurrency < 1 {
+			return fmt.Errorf("--requests and --concurrency must be positive")
+		}
+
+		mold, err := loadRequestMold(args[0])
+		if err != nil {
+			return err
+		}
+		profile, err := loadProfile("")
+		if err != nil {
+			return err
+		}
+		request, err := builder.BuildRequest(cmd.Context(), mold, profile)
+		if err != nil {
+			return fmt.Errorf("failed to build request: %w", err)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+		defer stop()
+		opts := bench.Options{
+			Concurrency: benchFlags.Concurrency,
+			Requests:    benchFlags.Requests,
+			Rate:        rate,
+		}
+		result := bench.Run(ctx, client.New(request.Insecure, opts.Concurrency), request, opts)
+
+		if benchFlags.Plain {
+			fmt.Print(print.SprintBench(result))
+		} else {
+			fmt.Print(print.SprintPrettyBench(result))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().BoolVarP(&benchFlags.Plain, "plain", "p", false, "Print plain results without styling")
+	benchCmd.Flags().IntVarP(&benchFlags.Concurrency, "concurrency", "c", 10, "Number of requests in flight at a time")
+	benchCmd.Flags().IntVarP(&benchFlags.Requests, "requests", "n", 100, "Number of requests to send")
+	benchCmd.Flags().StringVar(&benchFlags.Rate, "rate", "", "Maximum rate of requests, e.g. 50/s or 600/m (default is unlimited)")
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/loader"
+	"goful/core/paths"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+var editWorkspaceConfig bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and edit the configuration",
+	Long: `Show where the configuration and state are kept and which config file is in use.
+The config file is read from $XDG_CONFIG_HOME/goful/config.yaml, or ~/.goful.yaml when only that exists,
+unless --config is given. The .goful.yaml of the workspace is merged over it. The log is written to $XDG_STATE_HOME/goful/goful.log unless --log-file is given.`,
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the locations of the configuration, state and log",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile := viper.ConfigFileUsed()
+		if configFile == "" {
+			defaultConfigFile, err := paths.ConfigFile()
+			if err != nil {
+				return err
+			}
+			configFile = fmt.Sprintf("%s (not created, see goful config edit)", defaultConfigFile)
+		}
+		stateDir, err := paths.StateDir()
+		if err != nil {
+			return err
+		}
+		logFile := viper.GetString("log.file")
+		if logFile == "" {
+			logFile, err = paths.LogFile()
+			if err != nil {
+				return err
+			}
+		} else if logFile == "-" {
+			logFile = "stderr"
+		}
+
+		rows := [][]string{{"config", configFile}}
+		if workspaceConfigFile != "" {
+			rows = append(rows, []string{"workspace config", workspaceConfigFile})
+		}
+		rows = append(rows, [][]string{
+			{"state", stateDir},
+			{"log", logFile},
+		}...)
+		fmt.Print(sprintTable(rows))
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the configuration in effect, defaults included",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, err := yaml.Marshal(viper.AllSettings())
+		if err != nil {
+			return err
+		}
+		if configFile := viper.ConfigFileUsed(); configFile != "" {
+			fmt.Printf("# %s\n", configFile)
+		} else {
+			fmt.Println("# no config file, defaults only")
+		}
+		if workspaceConfigFile != "" {
+			fmt.Printf("# merged with %s\n", workspaceConfigFile)
+		}
+		fmt.Print(string(out))
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in $EDITOR, creating it when there is none",
+	Long: `Edit the user config file in $EDITOR, creating it when there is none.
+With --workspace-config the .goful.yaml of the workspace is edited instead, its settings are merged over
+the user config. Commit it to share settings of the workspace, such as the theme, defaults or redaction rules.
+Redaction rules of the workspace are added to those of the user. The editor, the log and whether exports are
+redacted can only be set in the user config.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configFile := viper.ConfigFileUsed()
+		if editWorkspaceConfig {
+			configFile = filepath.Join(viper.GetString("workspace"), loader.WorkspaceConfigFilename)
+		}
+		if configFile == "" {
+			var err error
+			configFile, err = paths.ConfigFile()
+			if err != nil {
+				return err
+			}
+		}
+		if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) {
+			if err := os.MkdirAll(filepath.Dir(configFile), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(configFile, []byte("# goful configuration, see goful config show for the settings in effect\n"), 0644); err != nil {
+				return err
+			}
+		}
+		return openInEditor(configFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configEditCmd)
+
+	configEditCmd.Flags().BoolVar(&editWorkspaceConfig, "workspace-config", false, "Edit the "+loader.WorkspaceConfigFilename+" of the workspace")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/diff"
+	"goful/core/history"
+	"goful/core/print"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type DiffFlags struct {
+	Plain         bool
+	IgnoreHeaders []string
+	IgnoreFields  []string
+}
+
+var diffFlags DiffFlags
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [RUN-A] [RUN-B]",
+	Short: "Compare the responses of two runs",
+	Long: `Compare the responses of two runs from the history, see "goful history" for run ids.
+Shows differing status lines, headers and JSON fields side by side.
+Volatile headers and fields are ignored, see diff.ignore_headers and diff.ignore_fields in the configuration.
+Fields are dot separated paths such as "items.*.updatedAt", a field without dots matches a key at any depth.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store := history.NewStore(viper.GetString("workspace"))
+		a, err := findHistoryResponse(store, args[0])
+		if err != nil {
+			return err
+		}
+		b, err := findHistoryResponse(store, args[1])
+		if err != nil {
+			return err
+		}
+
+		opts := diff.ConfiguredOptions()
+		opts.IgnoreHeaders = append(opts.IgnoreHeaders, diffFlags.IgnoreHeaders...)
+		opts.IgnoreFields = append(opts.IgnoreFields, diffFlags.IgnoreFields...)
+		result := diff.CompareResponses(a.Response, b.Response, opts)
+		if diffFlags.Plain {
+			fmt.Print(print.SprintDiff(result, a.Label(), b.Label()))
+		} else {
+			fmt.Print(print.SprintPrettyDiff(result, a.Label(), b.Label()))
+		}
+		return nil
+	},
+}
+
+func findHistoryResponse(store history.Store, run string) (history.Entry, error) {
+	id, err := strconv.Atoi(run)
+	if err != nil {
+		return history.Entry{}, fmt.Errorf("run must be a history id, got %s", run)
+	}
+	entry, err := store.Find(id)
+	if err != nil {
+		return entry, err
+	}
+	if entry.Response == nil {
+		return entry, fmt.Errorf("run %d has no response: %s", id, entry.Error)
+	}
+	return entry, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().BoolVarP(&diffFlags.Plain, "plain", "p", false, "Print plain diff without styling")
+	diffCmd.Flags().StringSliceVar(&diffFlags.IgnoreHeaders, "ignore-header", []string{}, "Additional headers to ignore")
+	diffCmd.Flags().StringSliceVar(&diffFlags.IgnoreFields, "ignore-field", []string{}, "Additional JSON fields to ignore")
+}
@@ -0,0 +1,27 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/viper"
+)
+
+// openInEditor opens the file in the configured editor and waits until the editor exits
+func openInEditor(path string) error {
+	editor := viper.GetString("editor")
+	if editor == "" {
+		return errors.New("editor is not configured, set editor in the configuration or $EDITOR")
+	}
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin, editorCmd.Stdout, editorCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("editor exited with error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/client/builder"
+	"goful/core/exporter"
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type ExportFlags struct {
+	Format   string
+	NoRedact bool
+}
+
+var exportFlags ExportFlags
+
+var exportCmd = &cobra.Command{
+	Use:   "export [NAME]...",
+	Short: "Export requests as curl, HTTPie, wget, Go code or a .http file",
+	Long: `Export a request as curl, HTTPie, wget or Go code. The request is built with the active profile applied.
+With --format http the named requests, or all requests when no names are given, are exported as a single .http file.
+Template variables are kept and the active profile is written as @var declarations.
+Credentials are redacted unless --no-redact is given or export.redact is false, a redacted export does not
+reproduce the request as is.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportFlags.Format == exporter.Http {
+			return exportHttpFile(args)
+		}
+		if len(args) != 1 {
+			return errors.New("exactly one request name is required")
+		}
+
+		mold, err := loadRequestMold(args[0])
+		if err != nil {
+			return err
+		}
+		profile, err := loadProfile("")
+		if err != nil {
+			return err
+		}
+		request, err := builder.BuildRequest(cmd.Context(), mold, profile)
+		if err != nil {
+			return fmt.Errorf("failed to build request: %w", err)
+		}
+		exported, err := exporter.Export(request, exportFlags.Format, redactExport())
+		if err != nil {
+			return err
+		}
+		fmt.Print(exported)
+		return nil
+	},
+}
+
+func exportHttpFile(names []string) error {
+	var molds []model.RequestMold
+	if len(names) == 0 {
+		all, err := loader.ReadRequests(viper.GetString("workspace"))
+		if err != nil {
+			return err
+		}
+		molds = all
+	} else {
+		for _, name := range names {
+			mold, err := loadRequestMold(name)
+			if err != nil {
+				return err
+			}
+			molds = append(molds, mold)
+		}
+	}
+	profile, err := loadProfile("")
+	if err != nil {
+		return err
+	}
+
+	exported, skipped, err := exporter.SprintHttpFile(molds, profile.Variables, profile.Defaults, redactExport())
+	if err != nil {
+		return err
+	}
+	fmt.Print(exported)
+	for _, name := range skipped {
+		fmt.Fprintf(os.Stderr, "Skipped request %s, Starlark requests can not be exported as .http\n", name)
+	}
+	return nil
+}
+
+// redactExport tells whether credentials are redacted from exports, which export.redact and --no-redact turn off
+func redactExport() bool {
+	return viper.GetBool("export.redact") && !exportFlags.NoRedact
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	formats := append(slices.Clone(exporter.Formats), exporter.Http)
+	exportCmd.Flags().StringVarP(&exportFlags.Format, "format", "f", exporter.Curl, fmt.Sprintf("Export format, one of following: %s", strings.Join(formats, ", ")))
+	exportCmd.Flags().BoolVar(&exportFlags.NoRedact, "no-redact", false, "Keep credentials in the export so that it reproduces the request as is")
+}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/history"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type HistoryFlags struct {
+	Limit  int
+	Search string
+}
+
+var historyFlags HistoryFlags
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List executed requests",
+	Long:  `List requests executed in the workspace, newest first. Responses can be browsed and run again in the requests TUI.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := history.NewStore(viper.GetString("workspace")).ReadAll()
+		if err != nil {
+			return err
+		}
+
+		printed := 0
+		for i := len(entries) - 1; i >= 0 && (historyFlags.Limit <= 0 || printed < historyFlags.Limit); i-- {
+			line := sprintHistoryLine(entries[i])
+			if historyFlags.Search != "" && !strings.Contains(strings.ToLower(line), strings.ToLower(historyFlags.Search)) {
+				continue
+			}
+			fmt.Println(line)
+			printed++
+		}
+		return nil
+	},
+}
+
+func sprintHistoryLine(entry history.Entry) string {
+	status := "failed"
+	if entry.Response != nil {
+		status = fmt.Sprint(entry.Response.StatusCode)
+	}
+	return fmt.Sprintf("%5d  %s  %-6s  %-8s  %-24s  %s %s",
+		entry.Id,
+		entry.Time.Local().Format("2006-01-02 15:04:05"),
+		status,
+		entry.Duration.Round(time.Millisecond),
+		entry.Name,
+		entry.Request.Method,
+		entry.Request.Url,
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().IntVarP(&historyFlags.Limit, "limit", "l", 20, "Maximum number of entries to list, 0 lists all")
+	historyCmd.Flags().StringVarP(&historyFlags.Search, "search", "s", "", "Only list entries containing this text")
+}
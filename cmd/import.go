@@ -0,0 +1,217 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/importer"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type ImportFlags struct {
+	Name string
+}
+
+type HarFlags struct {
+	Hosts        []string
+	Match        string
+	StripHeaders []string
+}
+
+var importFlags ImportFlags
+var harFlags HarFlags
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import requests from other formats",
+	Long:  `Import requests from other formats into the workspace`,
+}
+
+var importCurlCmd = &cobra.Command{
+	Use:   "curl [COMMAND]",
+	Short: "Import a request from a curl command line",
+	Long: `Import a request from a curl command line, e.g. one copied with "Copy as cURL" from browser devtools.
+The command must be given as a single quoted argument. When it is omitted or is "-", it is read from stdin.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var command string
+		if len(args) == 0 || args[0] == "-" {
+			b, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return err
+			}
+			command = string(b)
+		} else {
+			command = args[0]
+		}
+
+		yamlRequest, err := importer.ParseCurl(command)
+		if err != nil {
+			return fmt.Errorf("failed to parse curl command: %w", err)
+		}
+
+		yamlRequest.Name = importFlags.Name
+		if yamlRequest.Name == "" {
+			yamlRequest.Name = importer.SuggestName(yamlRequest)
+		}
+
+		mold, err := importer.SaveYamlRequest(viper.GetString("workspace"), yamlRequest)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Saved request %s to %s\n", mold.Name(), mold.Filename)
+		return nil
+	},
+}
+
+var importPostmanCmd = &cobra.Command{
+	Use:   "postman [FILE]...",
+	Short: "Import Postman v2.1 collections and environments",
+	Long: `Import Postman v2.1 collections and environments.
+Collections are converted into request files, one directory per Postman folder.
+Environments are converted into .env.<name> profiles. {{var}} references are converted to {var}.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := viper.GetString("workspace")
+		for _, filename := range args {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			var report importer.Report
+			if importer.IsPostmanCollection(data) {
+				report, err = importer.ImportPostmanCollection(data, root)
+			} else {
+				report, err = importer.ImportPostmanEnvironment(data, root)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %w", filename, err)
+			}
+			printImportReport(filename, report)
+		}
+		return nil
+	},
+}
+
+var importOpenApiCmd = &cobra.Command{
+	Use:   "openapi [FILE]",
+	Short: "Generate requests from an OpenAPI 3 specification",
+	Long: `Generate one request per operation of an OpenAPI 3 specification, grouped into one directory per tag.
+Path, query and header parameters are turned into template variables, which are written into the default profile
+with their example values. Optional parameters without an example are left out.
+Example bodies are generated from schemas.
+The first server url is written into the default profile as base_url.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		report, err := importer.ImportOpenApi(data, viper.GetString("workspace"))
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", args[0], err)
+		}
+		printImportReport(args[0], report)
+		return nil
+	},
+}
+
+var importHttpCmd = &cobra.Command{
+	Use:   "http [FILE]...",
+	Short: "Import requests from .http files",
+	Long: `Import requests from VS Code REST Client and JetBrains HTTP Client .http files.
+Each ### separated request is converted into a request file. @var declarations are written into the default profile
+and {{var}} references are converted to {var}. Response handlers and scripts are skipped.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := viper.GetString("workspace")
+		for _, filename := range args {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return err
+			}
+			report, err := importer.ImportHttpFile(data, filename, root)
+			if err != nil {
+				return fmt.Errorf("failed to import %s: %w", filename, err)
+			}
+			printImportReport(filename, report)
+		}
+		return nil
+	},
+}
+
+var importHarCmd = &cobra.Command{
+	Use:   "har [FILE]",
+	Short: "Import requests recorded in a HAR archive",
+	Long: `Import requests recorded in a HAR archive, e.g. one saved from browser devtools.
+Requests are numbered in the order they were recorded. Headers set by the browser or tied to the recorded session,
+such as Accept-Encoding, Content-Length and Sec-*, are stripped.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		filter, err := harFlags.filter()
+		if err != nil {
+			return err
+		}
+		report, err := importer.ImportHar(data, viper.GetString("workspace"), filter)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", args[0], err)
+		}
+		printImportReport(args[0], report)
+		return nil
+	},
+}
+
+func (f HarFlags) filter() (importer.HarFilter, error) {
+	filter := importer.HarFilter{
+		Hosts:        f.Hosts,
+		StripHeaders: f.StripHeaders,
+	}
+	if f.Match != "" {
+		pattern, err := regexp.Compile(f.Match)
+		if err != nil {
+			return filter, fmt.Errorf("invalid url pattern: %w", err)
+		}
+		filter.UrlPattern = pattern
+	}
+	return filter, nil
+}
+
+func addHarFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&harFlags.Hosts, "host", []string{}, "Only include requests to these hosts")
+	cmd.Flags().StringVar(&harFlags.Match, "match", "", "Only include requests whose url matches this regular expression")
+	cmd.Flags().StringSliceVar(&harFlags.StripHeaders, "strip-header", []string{}, "Additional headers to strip from requests")
+}
+
+func printImportReport(source string, report importer.Report) {
+	fmt.Printf("Imported %d files from %s\n", len(report.Files), source)
+	for _, f := range report.Files {
+		fmt.Printf("  %s\n", f)
+	}
+	if len(report.Warnings) > 0 {
+		fmt.Printf("Could not convert everything:\n")
+		for _, w := range report.Warnings {
+			fmt.Printf("  %s\n", w)
+		}
+	}
+}
+
+func init() {
+	importCurlCmd.Flags().StringVarP(&importFlags.Name, "name", "n", "", "Name of the imported request (default is derived from the method and url)")
+	importCmd.AddCommand(importCurlCmd)
+	importCmd.AddCommand(importPostmanCmd)
+	importCmd.AddCommand(importOpenApiCmd)
+	importCmd.AddCommand(importHttpCmd)
+	addHarFlags(importHarCmd)
+	importCmd.AddCommand(importHarCmd)
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/envcrypt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// maxPassphraseAttempts is how many wrong passphrases are asked again before giving up
+const maxPassphraseAttempts = 3
+
+var (
+	defaultPassphrase = envcrypt.Passphrase
+	// unlockedPassphrase decrypted a profile, it is not asked again during the run
+	unlockedPassphrase string
+	wrongPassphrases   int
+)
+
+func init() {
+	envcrypt.Passphrase = promptPassphrase
+	envcrypt.Unlocked = unlockPassphrase
+	envcrypt.Retry = retryPassphrase
+}
+
+// promptPassphrase asks the passphrase of encrypted profiles until one decrypts a profile, unless it is set in the
+// environment
+func promptPassphrase() (string, error) {
+	if unlockedPassphrase != "" {
+		return unlockedPassphrase, nil
+	}
+	if passphrase, err := defaultPassphrase(); err == nil {
+		return passphrase, nil
+	}
+	return readPassphrase("Passphrase: ")
+}
+
+func unlockPassphrase(passphrase string) {
+	unlockedPassphrase = passphrase
+	wrongPassphrases = 0
+}
+
+// retryPassphrase tells whether a wrong passphrase is asked again, which it is not when it is set in the environment
+func retryPassphrase(passphrase string) bool {
+	if _, err := defaultPassphrase(); err == nil {
+		return false
+	}
+	if passphrase == unlockedPassphrase {
+		// the profile was encrypted with another passphrase than the one unlocked so far
+		unlockedPassphrase = ""
+		return true
+	}
+	wrongPassphrases++
+	if wrongPassphrases >= maxPassphraseAttempts {
+		return false
+	}
+	fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+	return true
+}
+
+// promptNewPassphrase asks a passphrase to encrypt a profile with, twice to catch typos
+func promptNewPassphrase() (string, error) {
+	if passphrase, err := defaultPassphrase(); err == nil {
+		return passphrase, nil
+	}
+	passphrase, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	confirmed, err := readPassphrase("Confirm passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirmed {
+		return "", errors.New("passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+func readPassphrase(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("profile is encrypted, set %s or run in a terminal to enter the passphrase", envcrypt.PassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	if len(passphrase) == 0 {
+		return "", errors.New("passphrase must not be empty")
+	}
+	return string(passphrase), nil
+}
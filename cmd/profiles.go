@@ -4,26 +4,73 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"goful/core/loader"
 	profileManageTui "goful/tui/profile/manage"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // manageCmd represents the manage command
 var manageProfilesCmd = &cobra.Command{
 	Use:   "profiles",
-	Short: "A brief description of your command",
-	Long:  ``,
+	Short: "Manage profiles of the workspace",
+	Long: `Create, clone and delete profiles and edit their variables.
+Profiles are stored in .env.<name> files of the workspace, the default profile in .env.
+Comments of the files are kept when variables are changed. Encrypted profiles, .env.<name>.enc,
+are listed but edited with goful profiles edit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// TODO handle err
-		loadedProfiles, _ := loader.ReadProfiles("tmp")
-		profileManageTui.Start(loadedProfiles)
+		// the passphrase of encrypted profiles can not be asked once the TUI is running
+		if _, err := loader.ReadProfiles(viper.GetString("workspace")); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		profileManageTui.Start(viper.GetString("workspace"))
+	},
+}
+
+var resolveProfileCmd = &cobra.Command{
+	Use:   "resolve [NAME]",
+	Short: "Show the variables of a profile and where each value came from",
+	Long: `Show the variables of a profile after layering, the active profile when NAME is not given.
+Values are taken from the lowest precedence to the highest: .env, .env.<name>, .env.<name>.local,
+the environment for variables defined in the files and --var flags. Any of the files may be encrypted.`,
+	Example: `goful profiles resolve production --var token=abc`,
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := viper.GetString("profile")
+		if len(args) == 1 {
+			name = args[0]
+		}
+		overrides, err := parseVarOverrides(varOverrides)
+		if err != nil {
+			return err
+		}
+		layers, err := loader.ProfileLayers(viper.GetString("workspace"), name, overrides)
+		if err != nil {
+			return err
+		}
+
+		variables := loader.ResolveLayers(layers)
+		if len(variables) == 0 {
+			fmt.Printf("Profile %s has no variables\n", name)
+			return nil
+		}
+		rows := [][]string{{"KEY", "VALUE", "SOURCE", "OVERRIDES"}}
+		for _, v := range variables {
+			rows = append(rows, []string{v.Key, strings.ReplaceAll(v.Value, "\n", `\n`), v.Source, strings.Join(v.Overridden, ", ")})
+		}
+		fmt.Print(sprintTable(rows))
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(manageProfilesCmd)
+	manageProfilesCmd.AddCommand(resolveProfileCmd)
 
 	// Here you will define your flags and configuration settings.
 
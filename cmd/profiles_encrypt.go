@@ -0,0 +1,171 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"goful/core/envcrypt"
+	"goful/core/loader"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var decryptToStdout bool
+
+var encryptProfileCmd = &cobra.Command{
+	Use:   "encrypt NAME",
+	Short: "Encrypt the file of a profile so that it can be committed",
+	Long: `Encrypt .env.<name> into .env.<name>.enc with a passphrase and remove the plain file.
+The passphrase is read from $GOFUL_PASSPHRASE or asked. Encrypted profiles are decrypted in memory
+whenever they are used, with the same passphrase.`,
+	Example: `goful profiles encrypt staging`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plainPath, encryptedPath := profilePaths(args[0])
+		if _, err := os.Stat(encryptedPath); err == nil {
+			return fmt.Errorf("%s already exists, use goful profiles edit %s to change it", filepath.Base(encryptedPath), args[0])
+		}
+		plaintext, err := os.ReadFile(plainPath)
+		if err != nil {
+			return err
+		}
+		passphrase, err := promptNewPassphrase()
+		if err != nil {
+			return err
+		}
+		ciphertext, err := envcrypt.Encrypt(plaintext, passphrase)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(encryptedPath, ciphertext, 0644); err != nil {
+			return err
+		}
+		if err := os.Remove(plainPath); err != nil {
+			return err
+		}
+		fmt.Printf("Encrypted %s into %s\n", filepath.Base(plainPath), filepath.Base(encryptedPath))
+		return nil
+	},
+}
+
+var decryptProfileCmd = &cobra.Command{
+	Use:   "decrypt NAME",
+	Short: "Decrypt the file of an encrypted profile",
+	Long: `Decrypt .env.<name>.enc back into .env.<name> and remove the encrypted file.
+With --stdout the variables are printed instead and no files are touched.`,
+	Example: `goful profiles decrypt staging --stdout`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plainPath, encryptedPath := profilePaths(args[0])
+		plaintext, _, err := decryptProfileFile(encryptedPath)
+		if err != nil {
+			return err
+		}
+		if decryptToStdout {
+			_, err = os.Stdout.Write(plaintext)
+			return err
+		}
+		if _, err := os.Stat(plainPath); err == nil {
+			return fmt.Errorf("%s already exists", filepath.Base(plainPath))
+		}
+		if err := os.WriteFile(plainPath, plaintext, 0644); err != nil {
+			return err
+		}
+		if err := os.Remove(encryptedPath); err != nil {
+			return err
+		}
+		fmt.Printf("Decrypted %s into %s\n", filepath.Base(encryptedPath), filepath.Base(plainPath))
+		return nil
+	},
+}
+
+var editEncryptedProfileCmd = &cobra.Command{
+	Use:   "edit NAME",
+	Short: "Edit the file of an encrypted profile in $EDITOR",
+	Long: `Decrypt .env.<name>.enc into a temporary file, open it in the editor and encrypt it again with
+the same passphrase when the editor exits. The temporary file is removed afterwards.`,
+	Example: `goful profiles edit staging`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, encryptedPath := profilePaths(args[0])
+		plaintext, passphrase, err := decryptProfileFile(encryptedPath)
+		if err != nil {
+			return err
+		}
+
+		tmp, err := os.CreateTemp("", loader.ProfileFilename(args[0])+"-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		_, err = tmp.Write(plaintext)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := openInEditor(tmp.Name()); err != nil {
+			return err
+		}
+
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, plaintext) {
+			fmt.Printf("%s was not changed\n", filepath.Base(encryptedPath))
+			return nil
+		}
+		if _, err := godotenv.Unmarshal(string(edited)); err != nil {
+			return fmt.Errorf("%s was left unchanged, the edited profile is not a valid .env file: %w", filepath.Base(encryptedPath), err)
+		}
+		ciphertext, err := envcrypt.Encrypt(edited, passphrase)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(encryptedPath, ciphertext, 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Saved %s\n", filepath.Base(encryptedPath))
+		return nil
+	},
+}
+
+// profilePaths returns the paths of the plain and the encrypted file of profile name
+func profilePaths(name string) (string, string) {
+	root := viper.GetString("workspace")
+	return filepath.Join(root, loader.ProfileFilename(name)), filepath.Join(root, loader.EncryptedProfileFilename(name))
+}
+
+// decryptProfileFile returns the plaintext of an encrypted profile file and the passphrase that decrypted it
+func decryptProfileFile(path string) ([]byte, string, error) {
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+	passphrase, err := envcrypt.Passphrase()
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext, passphrase, err := envcrypt.Open(ciphertext, passphrase)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decrypt %s: %w", filepath.Base(path), err)
+	}
+	return plaintext, passphrase, nil
+}
+
+func init() {
+	manageProfilesCmd.AddCommand(encryptProfileCmd)
+	manageProfilesCmd.AddCommand(decryptProfileCmd)
+	manageProfilesCmd.AddCommand(editEncryptedProfileCmd)
+
+	decryptProfileCmd.Flags().BoolVar(&decryptToStdout, "stdout", false, "Print the variables instead of writing them to .env.<name>")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/client"
+	"goful/core/importer"
+	"goful/core/model"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+type ReplayFlags struct {
+	Insecure bool
+}
+
+var replayFlags ReplayFlags
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [FILE]",
+	Short: "Replay the requests recorded in a HAR archive",
+	Long: `Replay the requests recorded in a HAR archive one by one in the order they were recorded
+and compare the received status codes against the recorded ones.
+Exits with an error when any of the status codes differ.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return err
+		}
+		filter, err := harFlags.filter()
+		if err != nil {
+			return err
+		}
+		entries, err := importer.ReadHar(data, filter)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[0], err)
+		}
+
+		mismatches := 0
+		for i, entry := range entries {
+			request := model.Request{
+				Url:      entry.Request.Url,
+				Method:   entry.Request.Method,
+				Headers:  entry.Request.Headers,
+				Body:     entry.Request.Body,
+				Insecure: replayFlags.Insecure,
+			}
+			resp, err := client.DoRequest(cmd.Context(), request)
+			if err != nil {
+				mismatches++
+				fmt.Printf("%3d %-7s %s\n    recorded %d, failed: %v\n", i+1, request.Method, request.Url, entry.Status, err)
+				continue
+			}
+			result := "ok"
+			if resp.StatusCode != entry.Status {
+				mismatches++
+				result = "MISMATCH"
+			}
+			fmt.Printf("%3d %-7s %s\n    recorded %d, got %d %s\n", i+1, request.Method, request.Url, entry.Status, resp.StatusCode, result)
+		}
+
+		fmt.Printf("Replayed %d requests, %d differed from the recording\n", len(entries), mismatches)
+		if mismatches > 0 {
+			// differing responses are a result, not a usage error
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d of %d responses differed from the recording", mismatches, len(entries))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	addHarFlags(replayCmd)
+	replayCmd.Flags().BoolVarP(&replayFlags.Insecure, "insecure", "k", false, "Skip TLS certificate verification")
+}
@@ -4,10 +4,13 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"goful/core/loader"
 	requestManageTui "goful/tui/request/manage"
+	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // manageCmd represents the manage command
@@ -17,8 +20,13 @@ var manageCmd = &cobra.Command{
 	Long:  ``,
 	Run: func(cmd *cobra.Command, args []string) {
 		// TODO handle err
-		loadedRequests, _ := loader.ReadRequests("tmp")
-		requestManageTui.Start(loadedRequests)
+		loadedRequests, _ := loader.ReadRequests(viper.GetString("workspace"))
+		profile, err := loadProfile("")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		requestManageTui.Start(loadedRequests, profile)
 	},
 }
 
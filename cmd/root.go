@@ -4,8 +4,19 @@ Copyright © 2023 Teemu Turunen <teturun@gmail.com>
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"goful/core/diff"
+	"goful/core/history"
+	"goful/core/loader"
+	"goful/core/paths"
+	"goful/core/redact"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -15,6 +26,9 @@ import (
 
 var cfgFile string
 
+// workspaceConfigFile is the config file of the workspace merged over the user config, empty when there is none
+var workspaceConfigFile string
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "goful-cli",
@@ -42,25 +56,28 @@ func Execute() {
 }
 
 func init() {
+	// nothing is logged until the log is set up, the default logger would write to stderr
+	log.Logger = zerolog.Nop()
+
 	cobra.OnInitialize(initConfig)
-	runLogFile, _ := os.OpenFile(
-		"myapp.log",
-		os.O_APPEND|os.O_CREATE|os.O_WRONLY,
-		0664,
-	)
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
-		zerolog.TimeFieldFormat = zerolog.TimeFormatUnixMs
-		multi := zerolog.MultiLevelWriter(runLogFile)
-		log.Logger = zerolog.New(multi).With().Timestamp().Logger()
-		log.Info().Msg("Initialized logging")
-		return nil
+		return setupLogging()
 	}
 	rootCmd.PersistentFlags().Bool("help", false, "Displays help")
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.goful.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/goful/config.yaml or $HOME/.goful.yaml)")
+	rootCmd.PersistentFlags().String("workspace", "", "directory containing requests and profiles (default is tmp)")
+	viper.BindPFlag("workspace", rootCmd.PersistentFlags().Lookup("workspace"))
+	rootCmd.PersistentFlags().String("profile", "", "profile to apply to requests (default is default)")
+	viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+	rootCmd.PersistentFlags().StringArrayVar(&varOverrides, "var", []string{}, "override a variable of the profile, given as key=value")
+	rootCmd.PersistentFlags().String("log-level", "", "one of trace, debug, info, warn, error or disabled (default is info)")
+	viper.BindPFlag("log.level", rootCmd.PersistentFlags().Lookup("log-level"))
+	rootCmd.PersistentFlags().String("log-file", "", "file to log to, - for stderr (default is $XDG_STATE_HOME/goful/goful.log)")
+	viper.BindPFlag("log.file", rootCmd.PersistentFlags().Lookup("log-file"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -68,24 +85,164 @@ func initConfig() {
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		viper.AddConfigPath(home)
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".goful")
+	} else if configFile := findConfigFile(); configFile != "" {
+		viper.SetConfigFile(configFile)
 	}
 
 	viper.AutomaticEnv() // read in environment variables that match
 
+	viper.SetDefault("workspace", "tmp")
+	viper.SetDefault("profile", "default")
+	viper.SetDefault("export.format", "curl")
+	viper.SetDefault("export.redact", true)
+	viper.SetDefault("run.parallelism", 4)
+	viper.SetDefault("history.max_entries", history.DefaultMaxEntries)
+	viper.SetDefault("diff.ignore_headers", diff.DefaultIgnoreHeaders)
+	viper.SetDefault("diff.ignore_fields", []string{})
 	viper.SetDefault("theme.syntax", "native")
 	viper.SetDefault("printer.response.formatter", "terminal16m")
+	viper.SetDefault("redact.headers", redact.DefaultHeaders)
+	viper.SetDefault("redact.variables", redact.DefaultVariables)
+	viper.SetDefault("redact.patterns", redact.DefaultPatterns)
+	viper.SetDefault("log.level", zerolog.LevelInfoValue)
 
-	// If a config file is found, read it in.
+	// If a config file is found, read it in. Having none is fine.
 	if err := viper.ReadInConfig(); err != nil {
-		fmt.Printf("failed to read config %v\n", err)
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			fmt.Fprintf(os.Stderr, "failed to read config %v\n", err)
+		}
+	}
+	mergeWorkspaceConfig()
+
+	rules, err := redact.ConfiguredRules()
+	cobra.CheckErr(err)
+	redact.Configure(rules)
+}
+
+// findConfigFile returns the first existing config file, preferring the XDG location over the legacy one
+func findConfigFile() string {
+	for _, location := range []func() (string, error){paths.ConfigFile, paths.LegacyConfigFile} {
+		file, err := location()
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// workspaceConfigKeys are the settings, and the sections of settings, a workspace config may set. The others, such
+// as the editor and the log, stay in the user config, as anyone committing to the workspace could change them.
+var workspaceConfigKeys = []string{"theme", "printer", "profile", "run", "diff", "export.format", "history", "defaults", "profiles"}
+
+// workspaceRedactKeys are added to the redaction rules of the user config, a workspace can not remove any rule
+var workspaceRedactKeys = []string{"redact.headers", "redact.variables", "redact.patterns"}
+
+func isWorkspaceConfigKey(key string) bool {
+	for _, k := range workspaceConfigKeys {
+		if key == k || strings.HasPrefix(key, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// setNested sets a dot separated key, e.g. theme.syntax, into nested settings
+func setNested(settings map[string]interface{}, key string, value interface{}) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		nested, ok := settings[part].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			settings[part] = nested
+		}
+		settings = nested
+	}
+	settings[parts[len(parts)-1]] = value
+}
+
+// mergeWorkspaceConfig merges the config file of the workspace over the user config, so that a team can commit
+// settings of the workspace while individuals keep their own in the user config. Only workspaceConfigKeys are merged.
+func mergeWorkspaceConfig() {
+	file := filepath.Join(viper.GetString("workspace"), loader.WorkspaceConfigFilename)
+	info, err := os.Stat(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	// a workspace in the home directory has the legacy user config as its config
+	if userInfo, userErr := os.Stat(viper.ConfigFileUsed()); err == nil && userErr == nil && os.SameFile(info, userInfo) {
+		return
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read workspace config %v\n", err)
+		return
+	}
+	workspace := viper.New()
+	workspace.SetConfigType("yaml")
+	if err := workspace.ReadConfig(bytes.NewReader(data)); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read workspace config %s: %v\n", file, err)
+		return
+	}
+	keys := workspace.AllKeys()
+	slices.Sort(keys)
+	settings := make(map[string]interface{})
+	for _, key := range keys {
+		switch {
+		case slices.Contains(workspaceRedactKeys, key):
+			rules := viper.GetStringSlice(key)
+			for _, rule := range workspace.GetStringSlice(key) {
+				if !slices.Contains(rules, rule) {
+					rules = append(rules, rule)
+				}
+			}
+			setNested(settings, key, rules)
+		case isWorkspaceConfigKey(key):
+			setNested(settings, key, workspace.Get(key))
+		default:
+			fmt.Fprintf(os.Stderr, "ignored %s of workspace config %s, it can only be set in the user config\n", key, file)
+		}
+	}
+	if err := viper.MergeConfigMap(settings); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to merge workspace config %s: %v\n", file, err)
+		return
+	}
+	workspaceConfigFile = file
+}
+
+// setupLogging directs the log into log.file, by default in the state directory, at log.level
+func setupLogging() error {
+	level, err := zerolog.ParseLevel(viper.GetString("log.level"))
+	if err != nil {
+		return fmt.Errorf("invalid log level %s", viper.GetString("log.level"))
+	}
+
+	var out io.Writer = os.Stderr
+	logFile := viper.GetString("log.file")
+	if logFile != "-" {
+		if logFile == "" {
+			logFile, err = paths.LogFile()
+			if err != nil {
+				return err
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(logFile), 0755); err != nil {
+			return err
+		}
+		file, err := os.OpenFile(logFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file %w", err)
+		}
+		out = file
 	}
 
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnixMs
+	// credentials must not end up in the log
+	log.Logger = zerolog.New(redact.NewWriter(out)).Level(level).With().Timestamp().Logger()
+	log.Info().Msg("Initialized logging")
+	return nil
 }
@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"goful/core/client"
 	"goful/core/client/validator"
+	"goful/core/history"
 	"goful/core/model"
 	"goful/core/print"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 type RunConfig struct {
@@ -27,8 +30,10 @@ type RunArgs struct {
 }
 
 type RunFlags struct {
-	Body    string
-	Headers []string
+	Body     string
+	Headers  []string
+	Name     string
+	Profiles []string
 }
 
 var runConfig RunConfig
@@ -37,16 +42,27 @@ var runFlags RunFlags
 var runCmd = &cobra.Command{
 	Use:   "run [METHOD] [URL]",
 	Short: "Run a http request",
-	Long:  `Run a http request`,
+	Long: `Run a http request given as arguments or a request of the workspace with --name.
+With --profiles the named request is run concurrently once per profile and the results are compared
+against the first profile. Exits with an error when any of the results differ.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// Optionally run one of the validators provided by cobra
 		if err := cobra.RangeArgs(0, 2)(cmd, args); err != nil {
 			return err
 		}
 
+		if len(runFlags.Profiles) > 0 && runFlags.Name == "" {
+			return errors.New("--profiles can only be used with --name")
+		}
 		if len(args) == 0 {
+			if runFlags.Name == "" {
+				return errors.New("either METHOD and URL or --name is required")
+			}
 			return nil
 		}
+		if runFlags.Name != "" {
+			return errors.New("METHOD and URL can not be used with --name")
+		}
 
 		parsedArgs := ParseArgs(args)
 
@@ -59,20 +75,44 @@ var runCmd = &cobra.Command{
 
 		return nil
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var resp *model.Response
 		runArgs := ParseArgs(args)
 		if runArgs != (RunArgs{}) {
 			// TODO err
 			headers := toHeadersMap(runFlags.Headers)
-			resp, _ = client.DoRequest(model.Request{
+			request := model.Request{
 				Url:     runArgs.Url,
 				Method:  runArgs.Method,
 				Headers: headers,
 				Body:    runFlags.Body,
-			})
-		} else {
-			// TODO get from --name
+			}
+			started := time.Now()
+			var err error
+			resp, err = client.DoRequest(cmd.Context(), request)
+			history.Record(viper.GetString("workspace"), history.NewEntry("", "", request, resp, err, started))
+			if err != nil {
+				return err
+			}
+		} else if runFlags.Name != "" && len(runFlags.Profiles) > 0 {
+			return runAcrossProfiles(cmd, runFlags.Name, runFlags.Profiles)
+		} else if runFlags.Name != "" {
+			mold, err := loadRequestMold(runFlags.Name)
+			if err != nil {
+				return err
+			}
+			profile, err := loadProfile("")
+			if err != nil {
+				return err
+			}
+			result := runWithProfile(cmd.Context(), mold, profile)
+			if result.Err != nil {
+				return result.Err
+			}
+			resp = result.Response
+		}
+		if resp == nil {
+			return errors.New("no request was run")
 		}
 
 		var respStr string
@@ -87,6 +127,7 @@ var runCmd = &cobra.Command{
 			fmt.Print(fmt.Errorf("error %v", err))
 		}
 		fmt.Print(respStr)
+		return nil
 	},
 }
 
@@ -124,8 +165,11 @@ func init() {
 	runCmd.PersistentFlags().StringSlice("print", []string{}, fmt.Sprintf("Print WHAT\n- '%s'\tPrint response headers\n- '%s'\tPrint response body", printHeadersP, printBodyP))
 	runCmd.Flags().StringVarP(&runFlags.Body, "body", "b", "", "Request body")
 	runCmd.Flags().StringSliceVarP(&runFlags.Headers, "header", "h", []string{}, "Request headers formatted as HeaderName:HeaderValue")
+	runCmd.Flags().StringVarP(&runFlags.Name, "name", "n", "", "Name of a request in the workspace to run")
+	runCmd.Flags().StringSliceVar(&runFlags.Profiles, "profiles", []string{}, "Run the request once per profile and compare the results, e.g. dev,staging,prod")
 
-	runCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	// a PersistentPreRun would replace the one of rootCmd that sets up logging
+	runCmd.PreRun = func(cmd *cobra.Command, args []string) {
 		if cmd == runCmd {
 			printFlags, _ := cmd.Flags().GetStringSlice("print")
 			for _, flag := range printFlags {
@@ -0,0 +1,143 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goful/core/client"
+	"goful/core/client/builder"
+	"goful/core/diff"
+	"goful/core/history"
+	"goful/core/model"
+	"goful/core/print"
+	"goful/core/redact"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type profileRunResult struct {
+	Profile  model.Profile
+	Entry    history.Entry
+	Response *model.Response
+	Err      error
+}
+
+// runWithProfile builds the request with the profile, executes it and records it to history
+func runWithProfile(ctx context.Context, mold model.RequestMold, profile model.Profile) profileRunResult {
+	result := profileRunResult{Profile: profile}
+	request, err := builder.BuildRequest(ctx, mold, profile)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to build request: %w", err)
+		return result
+	}
+	started := time.Now()
+	resp, err := client.DoRequest(ctx, request)
+	result.Entry = history.Record(viper.GetString("workspace"), history.NewEntry(mold.Name(), profile.Name, request, resp, err, started))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Response = resp
+	return result
+}
+
+// runAcrossProfiles runs the request concurrently with each profile and compares the results against the first one
+func runAcrossProfiles(cmd *cobra.Command, name string, profileNames []string) error {
+	mold, err := loadRequestMold(name)
+	if err != nil {
+		return err
+	}
+	var profiles []model.Profile
+	var variables []map[string]string
+	for _, profileName := range profileNames {
+		profile, err := resolveProfile(profileName)
+		if err != nil {
+			return err
+		}
+		profiles = append(profiles, profile)
+		variables = append(variables, profile.Variables)
+	}
+	// the profiles run at the same time, so the values of all of them are redacted
+	redact.SetVariables(variables...)
+
+	results := make([]profileRunResult, len(profiles))
+	var wg sync.WaitGroup
+	for i, profile := range profiles {
+		wg.Add(1)
+		go func(i int, profile model.Profile) {
+			defer wg.Done()
+			results[i] = runWithProfile(cmd.Context(), mold, profile)
+		}(i, profile)
+	}
+	wg.Wait()
+
+	fmt.Print(sprintProfileRunTable(results))
+
+	baseline := results[0]
+	differing := 0
+	opts := diff.ConfiguredOptions()
+	for _, result := range results[1:] {
+		fmt.Println()
+		if baseline.Response == nil || result.Response == nil {
+			differing++
+			fmt.Printf("%s can not be compared to %s\n", result.Profile.Name, baseline.Profile.Name)
+			continue
+		}
+		comparison := diff.CompareResponses(baseline.Response, result.Response, opts)
+		if comparison.Equal() {
+			fmt.Printf("%s is identical to %s\n", result.Profile.Name, baseline.Profile.Name)
+			continue
+		}
+		differing++
+		fmt.Printf("%s differs from %s\n", result.Profile.Name, baseline.Profile.Name)
+		if runConfig.Plain {
+			fmt.Print(print.SprintDiff(comparison, baseline.Profile.Name, result.Profile.Name))
+		} else {
+			fmt.Print(print.SprintPrettyDiff(comparison, baseline.Profile.Name, result.Profile.Name))
+		}
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			differing = max(differing, 1)
+		}
+	}
+	if differing > 0 {
+		// differing results are a result, not a usage error
+		cmd.SilenceUsage = true
+		return errors.New("results differ between profiles")
+	}
+	return nil
+}
+
+func sprintProfileRunTable(results []profileRunResult) string {
+	rows := [][]string{{"PROFILE", "STATUS", "LATENCY", "SIZE", "RUN"}}
+	for _, result := range results {
+		if result.Err != nil {
+			rows = append(rows, []string{result.Profile.Name, fmt.Sprintf("failed: %v", result.Err), "", "", runId(result)})
+			continue
+		}
+		rows = append(rows, []string{
+			result.Profile.Name,
+			result.Response.Status,
+			result.Response.Duration.Round(time.Millisecond).String(),
+			fmt.Sprintf("%dB", result.Response.Size),
+			runId(result),
+		})
+	}
+
+	return sprintTable(rows)
+}
+
+func runId(result profileRunResult) string {
+	if result.Entry.Id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%d", result.Entry.Id)
+}
@@ -0,0 +1,28 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sprintTable aligns rows into columns, the first row being the header
+func sprintTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, col := range row {
+			widths[i] = max(widths[i], len(col))
+		}
+	}
+	var sb strings.Builder
+	for _, row := range rows {
+		cols := make([]string, len(row))
+		for i, col := range row {
+			cols[i] = fmt.Sprintf("%-*s", widths[i], col)
+		}
+		sb.WriteString(strings.TrimRight(strings.Join(cols, "  "), " ") + "\n")
+	}
+	return sb.String()
+}
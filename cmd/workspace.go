@@ -0,0 +1,67 @@
+/*
+Copyright © 2023 Teemu Turunen <teturun@gmail.com>
+*/
+package cmd
+
+import (
+	"fmt"
+	"goful/core/loader"
+	"goful/core/model"
+	"goful/core/redact"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// varOverrides are the --var flags overriding variables of the active profile
+var varOverrides []string
+
+func loadRequestMold(name string) (model.RequestMold, error) {
+	molds, err := loader.ReadRequests(viper.GetString("workspace"))
+	if err != nil {
+		return model.RequestMold{}, err
+	}
+	for _, mold := range molds {
+		if mold.Name() == name {
+			return mold, nil
+		}
+	}
+	return model.RequestMold{}, fmt.Errorf("request %s not found in workspace %s", name, viper.GetString("workspace"))
+}
+
+// loadProfile resolves the profile with the given name, or the configured active profile when name is empty.
+// Its variables are layered over the default profile and overridden by local files, the environment and --var flags.
+// A missing default profile is not an error, an empty profile is returned instead.
+// The values of its sensitive variables are redacted from then on, see redact.SetVariables.
+func loadProfile(name string) (model.Profile, error) {
+	profile, err := resolveProfile(name)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	redact.SetVariables(profile.Variables)
+	return profile, nil
+}
+
+func resolveProfile(name string) (model.Profile, error) {
+	if name == "" {
+		name = viper.GetString("profile")
+	}
+	overrides, err := parseVarOverrides(varOverrides)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	return loader.ResolveProfile(viper.GetString("workspace"), name, overrides)
+}
+
+// parseVarOverrides parses variables given as key=value
+func parseVarOverrides(vars []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	for _, v := range vars {
+		key, value, found := strings.Cut(v, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid --var %s, expected key=value", v)
+		}
+		overrides[key] = value
+	}
+	return overrides, nil
+}
@@ -0,0 +1,166 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"goful/core/client"
+	"goful/core/model"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Options controls how many requests are sent and how fast. Rate is requests per second, zero sends them as fast as
+// the workers can.
+type Options struct {
+	Concurrency int
+	Requests    int
+	Rate        float64
+}
+
+// Result summarizes a benchmark. Latencies are sorted and only contain the requests that received a response.
+type Result struct {
+	Requests    int
+	Failures    int
+	Elapsed     time.Duration
+	StatusCodes map[int]int
+	Errors      map[string]int
+	Latencies   []time.Duration
+}
+
+type sample struct {
+	latency    time.Duration
+	statusCode int
+	err        error
+}
+
+// ParseRate parses rates such as "50/s", "600/m" or "5/100ms". A number without a unit is per second.
+func ParseRate(rate string) (float64, error) {
+	if rate == "" {
+		return 0, nil
+	}
+	count, per, found := strings.Cut(rate, "/")
+	n, err := strconv.ParseFloat(strings.TrimSpace(count), 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid rate %s, expected e.g. 50/s", rate)
+	}
+	if !found {
+		return n, nil
+	}
+	per = strings.TrimSpace(per)
+	if per != "" && (per[0] < '0' || per[0] > '9') {
+		per = "1" + per
+	}
+	interval, err := time.ParseDuration(per)
+	if err != nil || interval <= 0 {
+		return 0, fmt.Errorf("invalid rate %s, expected e.g. 50/s", rate)
+	}
+	return n / interval.Seconds(), nil
+}
+
+// Run sends the request opts.Requests times with opts.Concurrency workers sharing c.
+// Cancelling ctx stops the benchmark, the result then covers the requests that were completed.
+func Run(ctx context.Context, c *client.Client, request model.Request, opts Options) Result {
+	concurrency := max(1, opts.Concurrency)
+	jobs := make(chan struct{})
+	samples := make(chan sample, concurrency)
+
+	started := time.Now()
+	go func() {
+		defer close(jobs)
+		var tick <-chan time.Time
+		if opts.Rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for i := 0; i < opts.Requests; i++ {
+			if tick != nil && i > 0 {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case jobs <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				sent := time.Now()
+				resp, err := c.Do(ctx, request)
+				if ctx.Err() != nil {
+					// requests aborted by the cancellation say nothing about the server
+					continue
+				}
+				s := sample{latency: time.Since(sent), err: err}
+				if err == nil {
+					s.statusCode = resp.StatusCode
+				}
+				samples <- s
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	result := Result{
+		StatusCodes: make(map[int]int),
+		Errors:      make(map[string]int),
+	}
+	for s := range samples {
+		result.Requests++
+		if s.err != nil {
+			result.Failures++
+			result.Errors[s.err.Error()]++
+			continue
+		}
+		if s.statusCode >= 400 {
+			result.Failures++
+		}
+		result.StatusCodes[s.statusCode]++
+		result.Latencies = append(result.Latencies, s.latency)
+	}
+	result.Elapsed = time.Since(started)
+	slices.Sort(result.Latencies)
+	return result
+}
+
+// Throughput is completed requests per second
+func (r Result) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// ErrorRate is the share of requests that received no response or a 4xx or 5xx status
+func (r Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Failures) / float64(r.Requests)
+}
+
+// Percentile returns the nearest rank latency for p between 0 and 100
+func (r Result) Percentile(p float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(r.Latencies))))
+	return r.Latencies[min(max(rank, 1), len(r.Latencies))-1]
+}
@@ -0,0 +1,151 @@
+package bench
+
+import (
+	"context"
+	"goful/core/client"
+	"goful/core/model"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// every tenth request fails
+		if received.Add(1)%10 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	request := model.Request{Url: server.URL, Method: "GET"}
+	result := Run(context.Background(), client.New(false, 5), request, Options{Concurrency: 5, Requests: 100})
+
+	if result.Requests != 100 {
+		t.Errorf("got %d, wanted %d", result.Requests, 100)
+	}
+	if int(received.Load()) != 100 {
+		t.Errorf("got %d, wanted %d", received.Load(), 100)
+	}
+	expectedStatusCodes := map[int]int{200: 90, 500: 10}
+	if !cmp.Equal(result.StatusCodes, expectedStatusCodes) {
+		t.Errorf("got\n%v\nwanted\n%v", result.StatusCodes, expectedStatusCodes)
+	}
+	if result.Failures != 10 || result.ErrorRate() != 0.1 {
+		t.Errorf("got %d failures and rate %v, wanted %d and %v", result.Failures, result.ErrorRate(), 10, 0.1)
+	}
+	if len(result.Latencies) != 100 {
+		t.Errorf("got %d, wanted %d", len(result.Latencies), 100)
+	}
+	if result.Percentile(50) > result.Percentile(90) || result.Percentile(90) > result.Percentile(99) {
+		t.Errorf("percentiles are not ordered: %v %v %v", result.Percentile(50), result.Percentile(90), result.Percentile(99))
+	}
+	if result.Throughput() <= 0 {
+		t.Errorf("got %v, wanted a positive throughput", result.Throughput())
+	}
+}
+
+func TestRunWithRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	request := model.Request{Url: server.URL, Method: "GET"}
+	result := Run(context.Background(), client.New(false, 4), request, Options{Concurrency: 4, Requests: 11, Rate: 100})
+
+	if result.Requests != 11 {
+		t.Errorf("got %d, wanted %d", result.Requests, 11)
+	}
+	// the first request is sent right away and the rest one every 10ms
+	if result.Elapsed < 100*time.Millisecond {
+		t.Errorf("got %v, wanted at least %v", result.Elapsed, 100*time.Millisecond)
+	}
+}
+
+func TestRunConnectionErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	request := model.Request{Url: server.URL, Method: "GET"}
+	result := Run(context.Background(), client.New(false, 2), request, Options{Concurrency: 2, Requests: 4})
+
+	if result.Requests != 4 || result.Failures != 4 {
+		t.Errorf("got %d requests and %d failures, wanted %d and %d", result.Requests, result.Failures, 4, 4)
+	}
+	if len(result.Errors) != 1 || len(result.StatusCodes) != 0 || len(result.Latencies) != 0 {
+		t.Errorf("got errors %v, status codes %v and %d latencies", result.Errors, result.StatusCodes, len(result.Latencies))
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	request := model.Request{Url: server.URL, Method: "GET"}
+	result := Run(ctx, client.New(false, 2), request, Options{Concurrency: 2, Requests: 1000})
+
+	if result.Requests == 0 || result.Requests >= 1000 {
+		t.Errorf("got %d, wanted some but not all requests", result.Requests)
+	}
+	if result.Failures != 0 {
+		t.Errorf("got %d, wanted no failures from cancelled requests", result.Failures)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	result := Result{Latencies: latencies}
+
+	for p, expected := range map[float64]time.Duration{
+		0:  time.Millisecond,
+		50: 50 * time.Millisecond,
+		90: 90 * time.Millisecond,
+		99: 99 * time.Millisecond,
+	} {
+		if got := result.Percentile(p); got != expected {
+			t.Errorf("p%v: got %v, wanted %v", p, got, expected)
+		}
+	}
+	if got := (Result{}).Percentile(50); got != 0 {
+		t.Errorf("got %v, wanted %v", got, 0)
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for rate, expected := range map[string]float64{
+		"":        0,
+		"50":      50,
+		"50/s":    50,
+		"600/m":   10,
+		"5/100ms": 50,
+		"3600/h":  1,
+	} {
+		got, err := ParseRate(rate)
+		if err != nil {
+			t.Errorf("did not expect error %v", err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("%s: got %v, wanted %v", rate, got, expected)
+		}
+	}
+
+	for _, rate := range []string{"fast", "-1/s", "50/x", "50/0s"} {
+		if _, err := ParseRate(rate); err == nil {
+			t.Errorf("expected error for %s", rate)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"goful/core/client/validator"
+	"goful/core/model"
+	"goful/core/templating/yamlng"
+	"strings"
+)
+
+// applyDefaults merges the defaults of the profile into a built request, values of the request take precedence.
+// Template variables of the defaults are processed with the variables of the profile.
+func applyDefaults(request model.Request, profile model.Profile) (model.Request, error) {
+	defaults := profile.Defaults
+
+	if baseUrl := processTemplate(defaults.BaseUrl, profile.Variables); baseUrl != "" && !validator.IsAbsoluteUrl(request.Url) {
+		request.Url = joinUrl(baseUrl, request.Url)
+	}
+
+	if len(defaults.Headers) > 0 {
+		headers := make(model.Headers)
+		for name, values := range defaults.Headers {
+			headers[name] = processTemplates(values, profile.Variables)
+		}
+		for name, values := range request.Headers {
+			for defaultName := range headers {
+				if strings.EqualFold(defaultName, name) {
+					delete(headers, defaultName)
+				}
+			}
+			headers[name] = values
+		}
+		request.Headers = headers
+	}
+
+	if len(defaults.Query) > 0 {
+		query := make(model.Query)
+		for name, values := range defaults.Query {
+			query[name] = processTemplates(values, profile.Variables)
+		}
+		requestUrl, err := appendQuery(request.Url, query)
+		if err != nil {
+			return request, err
+		}
+		request.Url = requestUrl
+	}
+	return request, nil
+}
+
+func joinUrl(baseUrl string, path string) string {
+	if path == "" {
+		return baseUrl
+	}
+	return strings.TrimRight(baseUrl, "/") + "/" + strings.TrimLeft(path, "/")
+}
+
+func processTemplate(s string, variables map[string]string) string {
+	for k, v := range variables {
+		s = yamlng.ProcessTemplateVariable(s, k, v)
+	}
+	return s
+}
+
+func processTemplates(values []string, variables map[string]string) []string {
+	processed := make([]string, len(values))
+	for i, v := range values {
+		processed[i] = processTemplate(v, variables)
+	}
+	return processed
+}
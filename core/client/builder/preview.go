@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"context"
+	"goful/core/model"
+	"goful/core/redact"
+	"goful/core/secrets"
+	"strings"
+)
+
+// kept template variables stay readable in a preview even when they are part of the query
+var templateBraces = strings.NewReplacer("%7B", "{", "%7D", "}")
+
+// PreviewUrl tells the url a request is sent to without building it, with the query and the defaults of the profile
+// applied. Secrets are not resolved and sensitive variables are not filled in, their template variables are kept.
+// Starlark requests are not run, their documented url is returned.
+func PreviewUrl(requestMold model.RequestMold, profile model.Profile) string {
+	if requestMold.Yaml == nil {
+		return requestMold.Url()
+	}
+
+	variables := make(map[string]string, len(profile.Variables))
+	for k, v := range profile.Variables {
+		if !secrets.IsRef(v) && !redact.IsSensitiveVariable(k) {
+			variables[k] = v
+		}
+	}
+	profile.Variables = variables
+
+	request, _, err := buildYamlRequest(context.Background(), requestMold, model.Response{}, profile)
+	if err == nil {
+		request, err = applyDefaults(request, profile)
+	}
+	if err != nil {
+		return requestMold.Url()
+	}
+	return redact.String(templateBraces.Replace(request.Url))
+}
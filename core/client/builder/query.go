@@ -0,0 +1,53 @@
+package builder
+
+import (
+	"fmt"
+	"goful/core/model"
+	"net/url"
+	"strings"
+)
+
+// mergeQuery adds the parameters of query to the url after the parameters the url already has, so a parameter
+// given in both is repeated. The query of the url is kept as is, the added parameters are url encoded.
+func mergeQuery(rawUrl string, query model.Query) (string, error) {
+	return addQuery(rawUrl, query, false)
+}
+
+// appendQuery adds the parameters of query that the url does not have yet, the query of the url is kept as is
+func appendQuery(rawUrl string, query model.Query) (string, error) {
+	return addQuery(rawUrl, query, true)
+}
+
+// addQuery works on the url as text, so that template variables left in the url are not escaped
+func addQuery(rawUrl string, query model.Query, onlyMissing bool) (string, error) {
+	base, fragment, hasFragment := strings.Cut(rawUrl, "#")
+	path, rawQuery, _ := strings.Cut(base, "?")
+	existing, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid query of url %s: %w", rawUrl, err)
+	}
+
+	added := url.Values{}
+	for name, values := range query {
+		if onlyMissing && existing.Has(name) {
+			continue
+		}
+		for _, v := range values {
+			added.Add(name, v)
+		}
+	}
+	if len(added) == 0 {
+		return rawUrl, nil
+	}
+
+	if rawQuery != "" {
+		rawQuery += "&" + added.Encode()
+	} else {
+		rawQuery = added.Encode()
+	}
+	merged := path + "?" + rawQuery
+	if hasFragment {
+		merged += "#" + fragment
+	}
+	return merged, nil
+}
@@ -1,7 +1,9 @@
 package builder
 
 import (
+	"context"
 	"goful/core/model"
+	"goful/core/redact"
 	starlarkng "goful/core/scripting/starlark"
 	"goful/core/templating/yamlng"
 	"reflect"
@@ -9,15 +11,24 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-var builders = []func(requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, bool, error){
+var builders = []func(ctx context.Context, requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, bool, error){
 	buildYamlRequest,
 	buildStarlarkRequest,
 }
 
-func BuildRequest(requestMold model.RequestMold, profile model.Profile) (model.Request, error) {
+// BuildRequest turns a request mold into a request with the profile applied. Secret references of the profile are
+// resolved here, so that only the requests that are built need the secrets. The defaults of the profile are merged
+// into the request, so that a request may have a url relative to the base url of the defaults.
+// Cancelling ctx interrupts building, e.g. a long running Starlark script.
+func BuildRequest(ctx context.Context, requestMold model.RequestMold, profile model.Profile) (model.Request, error) {
+	profile, err := resolveSecrets(ctx, requestMold, profile)
+	if err != nil {
+		return model.Request{}, err
+	}
+
 	var request model.Request
 	for _, builder := range builders {
-		result, accept, err := builder(requestMold, model.Response{}, profile)
+		result, accept, err := builder(ctx, requestMold, model.Response{}, profile)
 		if err != nil {
 			return model.Request{}, err
 		}
@@ -26,47 +37,66 @@ func BuildRequest(requestMold model.RequestMold, profile model.Profile) (model.R
 			break
 		}
 	}
-	return request, nil
+	return applyDefaults(request, profile)
 }
 
-func BuildRequestUsingPreviousResponse(requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, error) {
+func BuildRequestUsingPreviousResponse(ctx context.Context, requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, error) {
 	return model.Request{}, nil
 }
 
-func buildYamlRequest(requestMold model.RequestMold, _ model.Response, profile model.Profile) (model.Request, bool, error) {
+func buildYamlRequest(_ context.Context, requestMold model.RequestMold, _ model.Response, profile model.Profile) (model.Request, bool, error) {
 	if requestMold.Yaml == nil {
 		return model.Request{}, false, nil
 	}
 
 	yamlRequest := requestMold.Yaml
 
-	if len(profile.Variables) > 0 {
-		headers := yamlRequest.Headers
-		for k, v := range profile.Variables {
-			yamlRequest.Url = yamlng.ProcessTemplateVariable(yamlRequest.Url, k, v)
-			for headerName, headerValues := range yamlRequest.Headers {
-				headers[headerName] = yamlng.ProcessTemplateVariables(headerValues, k, v)
-			}
+	// process a copy so that the mold keeps its template variables for the next build
+	url := yamlRequest.Url
+	var headers model.Headers
+	if yamlRequest.Headers != nil {
+		headers = make(model.Headers)
+		for headerName, headerValues := range yamlRequest.Headers {
+			headers[headerName] = headerValues
+		}
+	}
+
+	for k, v := range profile.Variables {
+		url = yamlng.ProcessTemplateVariable(url, k, v)
+		for headerName, headerValues := range headers {
+			headers[headerName] = yamlng.ProcessTemplateVariables(headerValues, k, v)
+		}
+	}
+
+	if len(yamlRequest.Query) > 0 {
+		query := make(model.Query)
+		for name, values := range yamlRequest.Query {
+			query[name] = processTemplates(values, profile.Variables)
+		}
+		var err error
+		url, err = mergeQuery(url, query)
+		if err != nil {
+			return model.Request{}, true, err
 		}
-		yamlRequest.Headers = headers
 	}
 
 	request := model.Request{
-		Url:     yamlRequest.Url,
-		Method:  yamlRequest.Method,
-		Headers: yamlRequest.Headers,
-		Body:    yamlRequest.Body,
+		Url:      url,
+		Method:   yamlRequest.Method,
+		Headers:  headers,
+		Body:     yamlRequest.Body,
+		Insecure: yamlRequest.Insecure,
 	}
 
 	return request, true, nil
 }
 
-func buildStarlarkRequest(requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, bool, error) {
+func buildStarlarkRequest(ctx context.Context, requestMold model.RequestMold, previousResponse model.Response, profile model.Profile) (model.Request, bool, error) {
 	if requestMold.Starlark == nil {
 		return model.Request{}, false, nil
 	}
 
-	res, err := starlarkng.RunStarlarkScript(requestMold, previousResponse, profile)
+	res, err := starlarkng.RunStarlarkScript(ctx, requestMold, previousResponse, profile)
 	if err != nil {
 		log.Error().Err(err).Msg("Running Starlark script resulted to error")
 		return model.Request{}, true, err
@@ -86,7 +116,7 @@ func buildStarlarkRequest(requestMold model.RequestMold, previousResponse model.
 		}
 	}
 
-	log.Debug().Msgf("Converted headers %v", headers)
+	log.Debug().Msgf("Converted headers %v", redact.Headers(new(model.Headers).FromMap(headers)))
 
 	req := model.Request{
 		Url:     res["url"].(string),
@@ -95,7 +125,7 @@ func buildStarlarkRequest(requestMold model.RequestMold, previousResponse model.
 		Body:    res["body"],
 	}
 
-	log.Debug().Msgf("Built request %v", req)
+	log.Debug().Msgf("Built request %v", redact.Request(req))
 
 	return req, true, nil
 }
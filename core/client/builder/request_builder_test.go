@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"context"
 	"github.com/google/go-cmp/cmp"
 	"goful/core/model"
 	"math/big"
@@ -30,14 +31,14 @@ func TestBuildRequestYaml(t *testing.T) {
 		Body: "{\n  \"id\": 1,\n  \"name\": \"Jane\"\n}",
 	}
 
-	request, err := BuildRequest(requestMold, model.Profile{})
+	request, err := BuildRequest(context.Background(), requestMold, model.Profile{})
 	if err != nil {
 		t.Errorf("did not expect error %v", err)
 		return
 	}
 
 	if !cmp.Equal(request, wantedRequest) {
-		t.Errorf("got\n%q\nwanted\n%q\n", request, wantedRequest)
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
 	}
 }
 
@@ -73,14 +74,14 @@ func TestBuildRequestYamlWithTemplateVariables(t *testing.T) {
 		},
 	}
 
-	request, err := BuildRequest(requestMold, profile)
+	request, err := BuildRequest(context.Background(), requestMold, profile)
 	if err != nil {
 		t.Errorf("did not expect error %v", err)
 		return
 	}
 
 	if !cmp.Equal(request, wantedRequest) {
-		t.Errorf("got\n%q\nwanted\n%q\n", request, wantedRequest)
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
 	}
 }
 
@@ -115,17 +116,214 @@ body = {
 		Body: map[string]interface{}{
 			"id":     big.NewInt(1),
 			"amount": 1.2001,
-			"name":   "Jane1",
+			"name":   "Jane",
 		},
 	}
 
-	request, err := BuildRequest(requestMold, model.Profile{})
+	request, err := BuildRequest(context.Background(), requestMold, model.Profile{})
 	if err != nil {
 		t.Errorf("did not expect error %v", err)
 		return
 	}
 
 	if !cmp.Equal(request, wantedRequest, cmp.AllowUnexported(big.Int{})) {
-		t.Errorf("got\n%q\nwanted\n%q\n", request, wantedRequest)
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
+	}
+}
+
+func TestBuildRequestYamlResolvesUsedSecrets(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "http://foobar.com/api",
+			Method: "GET",
+			Headers: model.Headers{
+				"Authorization": {"Bearer {token}"},
+			},
+		},
+	}
+
+	profile := model.Profile{
+		Name: "test",
+		Variables: map[string]string{
+			"token":  "cmd://echo builder-test-token",
+			"unused": "cmd://exit 1",
+		},
+	}
+
+	request, err := BuildRequest(context.Background(), requestMold, profile)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedHeaders := model.Headers{
+		"Authorization": {"Bearer builder-test-token"},
+	}
+	if !cmp.Equal(request.Headers, wantedHeaders) {
+		t.Errorf("got\n%v\nwanted\n%v\n", request.Headers, wantedHeaders)
+	}
+	if profile.Variables["token"] != "cmd://echo builder-test-token" {
+		t.Errorf("expected profile to keep the secret reference, got %s", profile.Variables["token"])
+	}
+}
+
+func TestBuildRequestYamlWithDefaults(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "/users/{id}?fields=name",
+			Method: "GET",
+			Headers: model.Headers{
+				"accept": {"text/plain"},
+			},
+		},
+	}
+
+	profile := model.Profile{
+		Name: "test",
+		Variables: map[string]string{
+			"host":    "foobar.com",
+			"id":      "1",
+			"version": "2",
+		},
+		Defaults: model.Defaults{
+			BaseUrl: "https://{host}/api/",
+			Headers: model.Headers{
+				"Accept":     {"application/json"},
+				"User-Agent": {"goful"},
+			},
+			Query: model.Query{
+				"fields":      {"id"},
+				"api-version": {"{version}"},
+				"tag":         {"a b", "c"},
+			},
+		},
+	}
+
+	wantedRequest := model.Request{
+		Url:    "https://foobar.com/api/users/1?fields=name&api-version=2&tag=a+b&tag=c",
+		Method: "GET",
+		Headers: model.Headers{
+			"accept":     {"text/plain"},
+			"User-Agent": {"goful"},
+		},
+	}
+
+	request, err := BuildRequest(context.Background(), requestMold, profile)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if !cmp.Equal(request, wantedRequest) {
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
+	}
+}
+
+func TestBuildRequestYamlWithAbsoluteUrlKeepsIt(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "http://other.com/health",
+			Method: "GET",
+		},
+	}
+	profile := model.Profile{
+		Name:     "test",
+		Defaults: model.Defaults{BaseUrl: "https://foobar.com/api"},
+	}
+
+	request, err := BuildRequest(context.Background(), requestMold, profile)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if request.Url != "http://other.com/health" {
+		t.Errorf("got\n%v\nwanted\n%v\n", request.Url, "http://other.com/health")
+	}
+}
+
+func TestBuildRequestYamlWithQuery(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "http://foobar.com/search?sort=asc#results",
+			Method: "GET",
+			Query: model.Query{
+				"q":    {"{term}"},
+				"sort": {"desc"},
+				"tag":  {"a", "b&c"},
+			},
+		},
+	}
+	profile := model.Profile{
+		Name: "test",
+		Variables: map[string]string{
+			"term": "c++ & go",
+		},
+	}
+
+	request, err := BuildRequest(context.Background(), requestMold, profile)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wantedUrl := "http://foobar.com/search?sort=asc&q=c%2B%2B+%26+go&sort=desc&tag=a&tag=b%26c#results"
+	if request.Url != wantedUrl {
+		t.Errorf("got\n%v\nwanted\n%v\n", request.Url, wantedUrl)
+	}
+}
+
+func TestPreviewUrl(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "/users/{id}",
+			Method: "GET",
+			Query: model.Query{
+				"key":    {"{api_key}"},
+				"secret": {"{client}"},
+			},
+		},
+	}
+	profile := model.Profile{
+		Name: "test",
+		Variables: map[string]string{
+			"host":    "foobar.com",
+			"id":      "1",
+			"api_key": "very-secret-key",
+			"client":  "cmd://exit 1",
+		},
+		Defaults: model.Defaults{BaseUrl: "https://{host}"},
+	}
+
+	wantedUrl := "https://foobar.com/users/1?key={api_key}&secret={client}"
+	if previewUrl := PreviewUrl(requestMold, profile); previewUrl != wantedUrl {
+		t.Errorf("got\n%v\nwanted\n%v\n", previewUrl, wantedUrl)
+	}
+}
+
+func TestBuildRequestYamlWithUrlInQueryIsRelative(t *testing.T) {
+	requestMold := model.RequestMold{
+		Yaml: &model.YamlRequest{
+			Name:   "yaml_request",
+			Url:    "/redirect?to=https://other.com",
+			Method: "GET",
+		},
+	}
+	profile := model.Profile{
+		Name:     "test",
+		Defaults: model.Defaults{BaseUrl: "https://foobar.com/api"},
+	}
+
+	request, err := BuildRequest(context.Background(), requestMold, profile)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wantedUrl := "https://foobar.com/api/redirect?to=https://other.com"
+	if request.Url != wantedUrl {
+		t.Errorf("got\n%v\nwanted\n%v\n", request.Url, wantedUrl)
 	}
 }
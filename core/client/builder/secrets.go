@@ -0,0 +1,72 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"goful/core/model"
+	"goful/core/secrets"
+	"strings"
+)
+
+// resolveSecrets replaces the secret references of the profile with the secrets. Yaml requests only resolve the
+// variables they or the defaults of the profile use, Starlark scripts may read any variable of the profile.
+func resolveSecrets(ctx context.Context, requestMold model.RequestMold, profile model.Profile) (model.Profile, error) {
+	variables := make(map[string]string, len(profile.Variables))
+	for k, v := range profile.Variables {
+		if secrets.IsRef(v) && usesVariable(requestMold, profile.Defaults, k) {
+			secret, err := secrets.Resolve(ctx, v)
+			if err != nil {
+				return profile, fmt.Errorf("failed to resolve secret %s: %w", k, err)
+			}
+			v = secret
+		}
+		variables[k] = v
+	}
+	profile.Variables = variables
+	return profile, nil
+}
+
+func usesVariable(requestMold model.RequestMold, defaults model.Defaults, name string) bool {
+	if requestMold.Yaml == nil {
+		return true
+	}
+	templateVariable := fmt.Sprintf("{%s}", name)
+	if strings.Contains(requestMold.Yaml.Url, templateVariable) || strings.Contains(defaults.BaseUrl, templateVariable) {
+		return true
+	}
+	for _, queryValues := range queryOf(requestMold.Yaml.Query, defaults.Query) {
+		for _, v := range queryValues {
+			if strings.Contains(v, templateVariable) {
+				return true
+			}
+		}
+	}
+	for _, headerValues := range headersOf(requestMold.Yaml.Headers, defaults.Headers) {
+		for _, v := range headerValues {
+			if strings.Contains(v, templateVariable) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func headersOf(headers ...model.Headers) []model.HeaderValues {
+	var values []model.HeaderValues
+	for _, h := range headers {
+		for _, v := range h {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func queryOf(queries ...model.Query) []model.QueryValues {
+	var values []model.QueryValues
+	for _, q := range queries {
+		for _, v := range q {
+			values = append(values, v)
+		}
+	}
+	return values
+}
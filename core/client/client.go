@@ -1,17 +1,40 @@
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"github.com/go-resty/resty/v2"
 	"goful/core/model"
+	"net/http"
 )
 
-func DoRequest(request model.Request) (*model.Response, error) {
-	client := resty.New()
+// Client keeps its connections open between requests. It is safe for concurrent use.
+type Client struct {
+	resty *resty.Client
+}
+
+// New returns a client that keeps up to maxIdleConns connections per host open for reuse
+func New(insecure bool, maxIdleConns int) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = max(transport.MaxIdleConns, maxIdleConns)
+	transport.MaxIdleConnsPerHost = max(http.DefaultMaxIdleConnsPerHost, maxIdleConns)
+	if insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &Client{resty: resty.New().SetTransport(transport)}
+}
+
+// DoRequest executes the request. Cancelling ctx aborts the request.
+func DoRequest(ctx context.Context, request model.Request) (*model.Response, error) {
+	return New(request.Insecure, 0).Do(ctx, request)
+}
 
+// Do executes the request. Cancelling ctx aborts the request.
+func (c *Client) Do(ctx context.Context, request model.Request) (*model.Response, error) {
 	requestHeaders := request.Headers.ToMap()
 	// TODO enable trace?
 	// TODO handle body vs formdata, also check if []byte can be string before casting
-	resp, err := client.R().SetHeaders(requestHeaders).SetBody(request.Body).Execute(request.Method, request.Url)
+	resp, err := c.resty.R().SetContext(ctx).SetHeaders(requestHeaders).SetBody(request.Body).Execute(request.Method, request.Url)
 	if err != nil {
 		return &model.Response{}, err
 	}
@@ -23,6 +46,7 @@ func DoRequest(request model.Request) (*model.Response, error) {
 		Proto:      resp.Proto(),
 		Size:       resp.Size(),
 		ReceivedAt: resp.ReceivedAt(),
+		Duration:   resp.Time(),
 	}
 
 	return &r, nil
@@ -3,6 +3,7 @@ package validator
 import (
 	"net/http"
 	"net/url"
+	"regexp"
 	"slices"
 )
 
@@ -32,3 +33,10 @@ func IsValidUrl(rawUrl string) bool {
 	_, err := url.ParseRequestURI(rawUrl)
 	return err == nil
 }
+
+var absoluteUrlPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+
+// IsAbsoluteUrl tells whether rawUrl starts with a scheme, a url relative to a base url does not
+func IsAbsoluteUrl(rawUrl string) bool {
+	return absoluteUrlPattern.MatchString(rawUrl)
+}
@@ -0,0 +1,261 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"goful/core/model"
+	"net/http"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// DefaultIgnoreHeaders are headers that differ between any two responses
+var DefaultIgnoreHeaders = []string{"Date", "Age", "Expires", "X-Request-Id", "X-Amzn-Trace-Id", "Cf-Ray"}
+
+// Change describes a difference at Path. Values are rendered as JSON for bodies and as is for headers,
+// Old is empty when the value was added and New is empty when it was removed.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  string
+	New  string
+}
+
+// Options lists volatile parts of the responses to leave out of the comparison.
+// Fields are dot separated paths into a JSON body where array indices are segments too, e.g. "items.0.id".
+// Segments may use path.Match wildcards, e.g. "items.*.updatedAt". A field without dots matches a key at any depth.
+type Options struct {
+	IgnoreHeaders []string
+	IgnoreFields  []string
+}
+
+// ConfiguredOptions reads the volatile headers and fields from diff.ignore_headers and diff.ignore_fields.
+func ConfiguredOptions() Options {
+	return Options{
+		IgnoreHeaders: viper.GetStringSlice("diff.ignore_headers"),
+		IgnoreFields:  viper.GetStringSlice("diff.ignore_fields"),
+	}
+}
+
+type Result struct {
+	StatusA string
+	StatusB string
+	Headers []Change
+	Body    []Change
+	// BodyIsJson tells whether both bodies were compared structurally
+	BodyIsJson bool
+}
+
+func (r Result) Equal() bool {
+	return r.StatusA == r.StatusB && len(r.Headers) == 0 && len(r.Body) == 0
+}
+
+// CompareResponses compares status lines, headers and bodies of two responses.
+// Bodies that both parse as JSON are compared structurally, others as text.
+func CompareResponses(a *model.Response, b *model.Response, opts Options) Result {
+	result := Result{
+		StatusA: a.Status,
+		StatusB: b.Status,
+		Headers: CompareHeaders(a.Headers, b.Headers, opts),
+	}
+
+	jsonA, errA := decodeJson(a.Body)
+	jsonB, errB := decodeJson(b.Body)
+	if errA == nil && errB == nil {
+		result.BodyIsJson = true
+		result.Body = CompareJson(jsonA, jsonB, opts)
+	} else if !bytes.Equal(a.Body, b.Body) {
+		result.Body = []Change{{Kind: Changed, Old: string(a.Body), New: string(b.Body)}}
+	}
+	return result
+}
+
+// CompareHeaders compares header names case insensitively, multiple values are compared in order.
+func CompareHeaders(a model.Headers, b model.Headers, opts Options) []Change {
+	canonicalA := canonicalHeaders(a)
+	canonicalB := canonicalHeaders(b)
+
+	var names []string
+	for name := range canonicalA {
+		names = append(names, name)
+	}
+	for name := range canonicalB {
+		if _, ok := canonicalA[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []Change
+	for _, name := range names {
+		if slices.ContainsFunc(opts.IgnoreHeaders, func(ignored string) bool {
+			return strings.EqualFold(ignored, name)
+		}) {
+			continue
+		}
+		valueA, okA := canonicalA[name]
+		valueB, okB := canonicalB[name]
+		switch {
+		case !okA:
+			changes = append(changes, Change{Path: name, Kind: Added, New: valueB})
+		case !okB:
+			changes = append(changes, Change{Path: name, Kind: Removed, Old: valueA})
+		case valueA != valueB:
+			changes = append(changes, Change{Path: name, Kind: Changed, Old: valueA, New: valueB})
+		}
+	}
+	return changes
+}
+
+// CompareJson compares two decoded JSON values. Objects are compared by key and arrays by index.
+func CompareJson(a interface{}, b interface{}, opts Options) []Change {
+	var changes []Change
+	compareValues(nil, a, b, opts, &changes)
+	return changes
+}
+
+func compareValues(segments []string, a interface{}, b interface{}, opts Options, changes *[]Change) {
+	if isIgnored(segments, opts.IgnoreFields) {
+		return
+	}
+
+	switch valueA := a.(type) {
+	case map[string]interface{}:
+		if valueB, ok := b.(map[string]interface{}); ok {
+			var keys []string
+			for k := range valueA {
+				keys = append(keys, k)
+			}
+			for k := range valueB {
+				if _, ok := valueA[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				compareChild(append(slices.Clip(segments), k), valueA, valueB, k, opts, changes)
+			}
+			return
+		}
+	case []interface{}:
+		if valueB, ok := b.([]interface{}); ok {
+			for i := 0; i < max(len(valueA), len(valueB)); i++ {
+				childSegments := append(slices.Clip(segments), strconv.Itoa(i))
+				switch {
+				case i >= len(valueA):
+					addChange(childSegments, Added, nil, valueB[i], opts, changes)
+				case i >= len(valueB):
+					addChange(childSegments, Removed, valueA[i], nil, opts, changes)
+				default:
+					compareValues(childSegments, valueA[i], valueB[i], opts, changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: strings.Join(segments, "."), Kind: Changed, Old: renderJson(a), New: renderJson(b)})
+	}
+}
+
+func compareChild(segments []string, a map[string]interface{}, b map[string]interface{}, key string, opts Options, changes *[]Change) {
+	valueA, okA := a[key]
+	valueB, okB := b[key]
+	switch {
+	case !okA:
+		addChange(segments, Added, nil, valueB, opts, changes)
+	case !okB:
+		addChange(segments, Removed, valueA, nil, opts, changes)
+	default:
+		compareValues(segments, valueA, valueB, opts, changes)
+	}
+}
+
+func addChange(segments []string, kind ChangeKind, a interface{}, b interface{}, opts Options, changes *[]Change) {
+	if isIgnored(segments, opts.IgnoreFields) {
+		return
+	}
+	change := Change{Path: strings.Join(segments, "."), Kind: kind}
+	if kind == Removed {
+		change.Old = renderJson(a)
+	} else {
+		change.New = renderJson(b)
+	}
+	*changes = append(*changes, change)
+}
+
+func isIgnored(segments []string, ignoreFields []string) bool {
+	if len(segments) == 0 {
+		return false
+	}
+	for _, field := range ignoreFields {
+		patterns := strings.Split(field, ".")
+		if len(patterns) == 1 {
+			if matched, _ := path.Match(patterns[0], segments[len(segments)-1]); matched {
+				return true
+			}
+			continue
+		}
+		if len(patterns) != len(segments) {
+			continue
+		}
+		matched := true
+		for i, pattern := range patterns {
+			if ok, _ := path.Match(pattern, segments[i]); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func canonicalHeaders(headers model.Headers) map[string]string {
+	canonical := make(map[string]string)
+	for name, values := range headers {
+		canonical[http.CanonicalHeaderKey(name)] = strings.Join(values, ", ")
+	}
+	return canonical
+}
+
+func decodeJson(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// numbers are kept as written so that large integers compare exactly
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func renderJson(value interface{}) string {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(b)
+}
@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"goful/core/model"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompareResponses(t *testing.T) {
+	a := &model.Response{
+		Status: "200 OK",
+		Headers: model.Headers{
+			"Content-Type": {"application/json"},
+			"Date":         {"Fri, 01 Mar 2024 10:00:00 GMT"},
+			"x-version":    {"1.2.0"},
+			"X-Removed":    {"foo"},
+		},
+		Body: []byte(`{
+			"id": 12345678901234567890,
+			"name": "Jane",
+			"updatedAt": "2024-03-01T10:00:00Z",
+			"roles": ["admin", "user"],
+			"address": {"city": "Helsinki", "zip": "00100"},
+			"items": [{"id": 1, "etag": "a"}, {"id": 2, "etag": "b"}]
+		}`),
+	}
+	b := &model.Response{
+		Status: "200 OK",
+		Headers: model.Headers{
+			"Content-Type": {"application/json"},
+			"Date":         {"Fri, 01 Mar 2024 10:00:05 GMT"},
+			"X-Version":    {"1.3.0"},
+			"X-Added":      {"bar"},
+		},
+		Body: []byte(`{
+			"id": 12345678901234567891,
+			"name": "Jane",
+			"updatedAt": "2024-03-01T10:00:05Z",
+			"roles": ["admin"],
+			"address": {"city": "Espoo"},
+			"items": [{"id": 1, "etag": "c"}, {"id": 2, "etag": "d"}],
+			"active": true
+		}`),
+	}
+
+	result := CompareResponses(a, b, Options{
+		IgnoreHeaders: DefaultIgnoreHeaders,
+		IgnoreFields:  []string{"updatedAt", "items.*.etag"},
+	})
+
+	wanted := Result{
+		StatusA: "200 OK",
+		StatusB: "200 OK",
+		Headers: []Change{
+			{Path: "X-Added", Kind: Added, New: "bar"},
+			{Path: "X-Removed", Kind: Removed, Old: "foo"},
+			{Path: "X-Version", Kind: Changed, Old: "1.2.0", New: "1.3.0"},
+		},
+		Body: []Change{
+			{Path: "active", Kind: Added, New: "true"},
+			{Path: "address.city", Kind: Changed, Old: `"Helsinki"`, New: `"Espoo"`},
+			{Path: "address.zip", Kind: Removed, Old: `"00100"`},
+			{Path: "id", Kind: Changed, Old: "12345678901234567890", New: "12345678901234567891"},
+			{Path: "roles.1", Kind: Removed, Old: `"user"`},
+		},
+		BodyIsJson: true,
+	}
+
+	if !cmp.Equal(result, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", result, wanted)
+	}
+	if result.Equal() {
+		t.Errorf("did not expect responses to be equal")
+	}
+}
+
+func TestCompareResponsesWithTextBodies(t *testing.T) {
+	a := &model.Response{Status: "200 OK", Body: []byte("hello")}
+	b := &model.Response{Status: "404 Not Found", Body: []byte("not found")}
+
+	result := CompareResponses(a, b, Options{})
+
+	wanted := Result{
+		StatusA: "200 OK",
+		StatusB: "404 Not Found",
+		Body:    []Change{{Kind: Changed, Old: "hello", New: "not found"}},
+	}
+	if !cmp.Equal(result, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", result, wanted)
+	}
+}
+
+func TestCompareEqualResponses(t *testing.T) {
+	a := &model.Response{Status: "200 OK", Body: []byte(`{"a": [1, 2], "b": {"c": null}}`)}
+	b := &model.Response{Status: "200 OK", Body: []byte(`{"b": {"c": null}, "a": [1, 2]}`)}
+
+	result := CompareResponses(a, b, Options{})
+	if !result.Equal() {
+		t.Errorf("expected responses to be equal, got\n%v", result)
+	}
+}
+
+func TestCompareJsonWithIgnoredSubtree(t *testing.T) {
+	a := map[string]interface{}{"meta": map[string]interface{}{"took": 1.0}, "ok": true}
+	b := map[string]interface{}{"meta": map[string]interface{}{"took": 2.0, "node": "b"}, "ok": true}
+
+	changes := CompareJson(a, b, Options{IgnoreFields: []string{"meta"}})
+	if len(changes) != 0 {
+		t.Errorf("got %v, wanted no changes", changes)
+	}
+}
@@ -0,0 +1,104 @@
+package envcrypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// PassphraseEnv names the environment variable holding the passphrase of encrypted profile files
+const PassphraseEnv = "GOFUL_PASSPHRASE"
+
+var ErrWrongPassphrase = errors.New("wrong passphrase")
+
+// Passphrase returns the passphrase of encrypted profile files, by default from PassphraseEnv.
+// Commands running in a terminal replace it to ask the passphrase when the variable is not set.
+var Passphrase = func() (string, error) {
+	passphrase, ok := os.LookupEnv(PassphraseEnv)
+	if !ok || passphrase == "" {
+		return "", fmt.Errorf("profile is encrypted but %s is not set", PassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+// Unlocked is called with a passphrase once it decrypted a file, commands asking the passphrase keep it from there on
+var Unlocked = func(passphrase string) {}
+
+// Retry is called with a passphrase that failed to decrypt a file and tells whether Passphrase should be asked again.
+// The passphrase of PassphraseEnv can not change, so by default it is not.
+var Retry = func(passphrase string) bool {
+	return false
+}
+
+// workFactor is the scrypt work factor of encrypted files, zero for the default of age
+var workFactor = 0
+
+// Encrypt encrypts plaintext with an age scrypt recipient derived from passphrase. The result is ASCII armored,
+// so that it can be committed like any other text file.
+func Encrypt(plaintext []byte, passphrase string) ([]byte, error) {
+	recipient, err := age.NewScryptRecipient(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if workFactor > 0 {
+		recipient.SetWorkFactor(workFactor)
+	}
+
+	var buf bytes.Buffer
+	armorWriter := armor.NewWriter(&buf)
+	w, err := age.Encrypt(armorWriter, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := armorWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decrypt decrypts ciphertext written by Encrypt
+func Decrypt(ciphertext []byte, passphrase string) ([]byte, error) {
+	identity, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	r, err := age.Decrypt(armor.NewReader(bytes.NewReader(ciphertext)), identity)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, ErrWrongPassphrase
+		}
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// Open decrypts ciphertext with passphrase, asking Passphrase for another one as long as it is wrong and Retry allows.
+// It returns the plaintext and the passphrase that decrypted it, which is also passed to Unlocked.
+func Open(ciphertext []byte, passphrase string) ([]byte, string, error) {
+	for {
+		plaintext, err := Decrypt(ciphertext, passphrase)
+		if err == nil {
+			Unlocked(passphrase)
+			return plaintext, passphrase, nil
+		}
+		if !errors.Is(err, ErrWrongPassphrase) || !Retry(passphrase) {
+			return nil, "", err
+		}
+		passphrase, err = Passphrase()
+		if err != nil {
+			return nil, "", err
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package envcrypt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func init() {
+	// the default work factor takes a second per file
+	workFactor = 10
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	plaintext := []byte("# staging\ntoken=abc123\n")
+
+	ciphertext, err := Encrypt(plaintext, "correct horse")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	if !bytes.HasPrefix(ciphertext, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		t.Errorf("expected armored ciphertext, got\n%s", ciphertext)
+	}
+	if bytes.Contains(ciphertext, []byte("abc123")) {
+		t.Errorf("expected ciphertext not to contain the plaintext")
+	}
+
+	decrypted, err := Decrypt(ciphertext, "correct horse")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("got\n%s\nwanted\n%s", decrypted, plaintext)
+	}
+}
+
+func TestDecryptWrongPassphrase(t *testing.T) {
+	ciphertext, err := Encrypt([]byte("token=abc123\n"), "correct horse")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+
+	_, err = Decrypt(ciphertext, "battery staple")
+	if !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+}
+
+func TestDecryptInvalid(t *testing.T) {
+	_, err := Decrypt([]byte("token=abc123\n"), "correct horse")
+	if err == nil {
+		t.Errorf("expected error for a file that is not encrypted")
+	}
+}
+
+func TestPassphraseFromEnvironment(t *testing.T) {
+	t.Setenv(PassphraseEnv, "from env")
+	passphrase, err := Passphrase()
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if passphrase != "from env" {
+		t.Errorf("got\n%v\nwanted\n%v", passphrase, "from env")
+	}
+
+	t.Setenv(PassphraseEnv, "")
+	_, err = Passphrase()
+	if err == nil {
+		t.Errorf("expected error when %s is not set", PassphraseEnv)
+	}
+}
+
+func TestOpenAsksAgain(t *testing.T) {
+	ciphertext, err := Encrypt([]byte("token=abc123\n"), "correct horse")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	defer func(passphrase func() (string, error), retry func(string) bool, unlocked func(string)) {
+		Passphrase, Retry, Unlocked = passphrase, retry, unlocked
+	}(Passphrase, Retry, Unlocked)
+
+	var retried []string
+	var unlocked string
+	Passphrase = func() (string, error) { return "correct horse", nil }
+	Retry = func(passphrase string) bool {
+		retried = append(retried, passphrase)
+		return true
+	}
+	Unlocked = func(passphrase string) { unlocked = passphrase }
+
+	plaintext, passphrase, err := Open(ciphertext, "battery staple")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if string(plaintext) != "token=abc123\n" || passphrase != "correct horse" || unlocked != "correct horse" {
+		t.Errorf("got\n%s %v %v\nwanted\n%s %v %v", plaintext, passphrase, unlocked, "token=abc123\n", "correct horse", "correct horse")
+	}
+	if len(retried) != 1 || retried[0] != "battery staple" {
+		t.Errorf("got\n%v\nwanted\n%v", retried, []string{"battery staple"})
+	}
+
+	Retry = func(string) bool { return false }
+	unlocked = ""
+	_, _, err = Open(ciphertext, "battery staple")
+	if !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("expected ErrWrongPassphrase, got %v", err)
+	}
+	if unlocked != "" {
+		t.Errorf("expected a wrong passphrase not to be unlocked, got %v", unlocked)
+	}
+}
@@ -0,0 +1,168 @@
+package envfile
+
+import (
+	"regexp"
+	"strings"
+)
+
+// doubleQuoteSpecialChars are escaped inside double quotes the same way godotenv escapes them
+const doubleQuoteSpecialChars = "\\\n\r\"!$`"
+
+var (
+	keyPattern      = regexp.MustCompile(`^\s*(export\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*[=:]\s*`)
+	unquotedPattern = regexp.MustCompile(`^[A-Za-z0-9_./:@+,-]*$`)
+	inlineComment   = regexp.MustCompile(`\s+#`)
+	validKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+// File is a dotenv file that can be modified without losing its comments, blank lines and the order of variables
+type File struct {
+	entries []entry
+}
+
+// entry is a variable spanning one or more lines, or a line without a variable such as a comment when key is empty
+type entry struct {
+	key string
+	// position of the key on the first line
+	keyStart int
+	keyEnd   int
+	// export keyword of the variable, kept when its value is replaced
+	prefix string
+	// inline comment after the value, kept when the value is replaced
+	comment string
+	raw     string
+}
+
+// Parse splits a dotenv file into its variables and the lines in between. Values are not interpreted,
+// use godotenv to read them.
+func Parse(data []byte) *File {
+	f := &File{}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		match := keyPattern.FindStringSubmatchIndex(line)
+		if match == nil || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			f.entries = append(f.entries, entry{raw: line})
+			continue
+		}
+
+		e := entry{key: line[match[4]:match[5]], keyStart: match[4], keyEnd: match[5]}
+		if match[2] >= 0 {
+			e.prefix = line[match[2]:match[3]]
+		}
+		value := line[match[1]:]
+		raw := line
+		if len(value) > 0 && (value[0] == '"' || value[0] == '\'') {
+			// quoted values may continue on the following lines
+			rest := value
+			closing := closingQuote(rest, 1, value[0])
+			for closing < 0 && i+1 < len(lines) {
+				i++
+				raw += "\n" + lines[i]
+				rest = lines[i]
+				closing = closingQuote(rest, 0, value[0])
+			}
+			if closing >= 0 {
+				e.comment = rest[closing+1:]
+			}
+		} else if loc := inlineComment.FindStringIndex(value); loc != nil {
+			e.comment = value[loc[0]:]
+		}
+		e.raw = raw
+		f.entries = append(f.entries, e)
+	}
+	return f
+}
+
+// Keys returns the names of the variables in the order they appear
+func (f *File) Keys() []string {
+	var keys []string
+	for _, e := range f.entries {
+		if e.key != "" {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+// Set replaces the value of key in place or appends the variable to the end of the file
+func (f *File) Set(key string, value string) {
+	for i, e := range f.entries {
+		if e.key == key {
+			f.entries[i].raw = e.prefix + key + "=" + Quote(value) + e.comment
+			f.entries[i].keyStart = len(e.prefix)
+			f.entries[i].keyEnd = len(e.prefix) + len(key)
+			return
+		}
+	}
+	f.entries = append(f.entries, entry{key: key, keyEnd: len(key), raw: key + "=" + Quote(value)})
+}
+
+// Rename changes the name of a variable keeping its value and position. It returns false when oldKey does not exist.
+func (f *File) Rename(oldKey string, newKey string) bool {
+	for i, e := range f.entries {
+		if e.key == oldKey {
+			f.entries[i].key = newKey
+			f.entries[i].keyEnd = e.keyStart + len(newKey)
+			f.entries[i].raw = e.raw[:e.keyStart] + newKey + e.raw[e.keyEnd:]
+			return true
+		}
+	}
+	return false
+}
+
+// Delete removes key and returns false when it does not exist. Comments above the variable are kept.
+func (f *File) Delete(key string) bool {
+	for i, e := range f.entries {
+		if e.key == key {
+			f.entries = append(f.entries[:i], f.entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (f *File) Bytes() []byte {
+	var sb strings.Builder
+	for _, e := range f.entries {
+		sb.WriteString(e.raw)
+		sb.WriteString("\n")
+	}
+	return []byte(sb.String())
+}
+
+// closingQuote finds the end of a quoted value starting the search at from. Only double quotes can be escaped.
+func closingQuote(value string, from int, quote byte) int {
+	for i := from; i < len(value); i++ {
+		if value[i] == quote && (quote == '\'' || i == 0 || value[i-1] != '\\') {
+			return i
+		}
+	}
+	return -1
+}
+
+// Quote formats a value so that godotenv reads it back as is. Simple values are left unquoted.
+func Quote(value string) string {
+	if value != "" && unquotedPattern.MatchString(value) {
+		return value
+	}
+	for _, c := range doubleQuoteSpecialChars {
+		replacement := `\` + string(c)
+		switch c {
+		case '\n':
+			replacement = `\n`
+		case '\r':
+			replacement = `\r`
+		}
+		value = strings.ReplaceAll(value, string(c), replacement)
+	}
+	return `"` + value + `"`
+}
+
+// IsValidKey tells whether key can be written as a variable name
+func IsValidKey(key string) bool {
+	return validKeyPattern.MatchString(key)
+}
@@ -0,0 +1,103 @@
+package envfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/joho/godotenv"
+)
+
+const original = `# shared settings
+domain=foobar.com # the api host
+
+export token="abc
+def"
+# port of the api
+port=8080
+`
+
+func TestParseKeepsContent(t *testing.T) {
+	f := Parse([]byte(original))
+
+	if got := string(f.Bytes()); got != original {
+		t.Errorf("got\n%v\nwanted\n%v", got, original)
+	}
+	if !cmp.Equal(f.Keys(), []string{"domain", "token", "port"}) {
+		t.Errorf("got\n%v\nwanted\n%v", f.Keys(), []string{"domain", "token", "port"})
+	}
+}
+
+func TestModify(t *testing.T) {
+	f := Parse([]byte(original))
+	f.Set("domain", "foobarprod.com")
+	f.Set("token", "xyz")
+	f.Set("user", "Jane Doe")
+	f.Rename("port", "export_port")
+	f.Delete("missing")
+
+	expected := `# shared settings
+domain=foobarprod.com # the api host
+
+export token=xyz
+# port of the api
+export_port=8080
+user="Jane Doe"
+`
+	if got := string(f.Bytes()); got != expected {
+		t.Errorf("got\n%v\nwanted\n%v", got, expected)
+	}
+
+	if !f.Delete("token") {
+		t.Errorf("expected token to be deleted")
+	}
+	if f.Rename("token", "other") {
+		t.Errorf("did not expect a deleted key to be renamed")
+	}
+	expectedKeys := []string{"domain", "export_port", "user"}
+	if !cmp.Equal(f.Keys(), expectedKeys) {
+		t.Errorf("got\n%v\nwanted\n%v", f.Keys(), expectedKeys)
+	}
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	values := map[string]string{
+		"empty":    "",
+		"simple":   "foobar.com",
+		"spaces":   "Jane Doe",
+		"template": "https://{domain}/users",
+		"quotes":   `say "hi" and 'bye'`,
+		"dollar":   "pa$$word",
+		"newline":  "line1\nline2",
+		"hash":     "#notacomment",
+		"backtick": "a`b\\c",
+	}
+
+	f := Parse(nil)
+	for k, v := range values {
+		f.Set(k, v)
+	}
+	parsed, err := godotenv.Parse(bytes.NewReader(f.Bytes()))
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if !cmp.Equal(parsed, values) {
+		t.Errorf("got\n%v\nwanted\n%v", parsed, values)
+	}
+}
+
+func TestIsValidKey(t *testing.T) {
+	for key, expected := range map[string]bool{
+		"domain":     true,
+		"API_TOKEN":  true,
+		"a.b-c":      true,
+		"":           false,
+		"1st":        false,
+		"with space": false,
+	} {
+		if got := IsValidKey(key); got != expected {
+			t.Errorf("%s: got %v, wanted %v", key, got, expected)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"goful/core/model"
+	"goful/core/redact"
+	"sort"
+	"strings"
+)
+
+const (
+	Curl   = "curl"
+	Httpie = "httpie"
+	Wget   = "wget"
+	Go     = "go"
+)
+
+var Formats = []string{Curl, Httpie, Wget, Go}
+
+var exporters = map[string]func(request model.Request) (string, error){
+	Curl:   exportCurl,
+	Httpie: exportHttpie,
+	Wget:   exportWget,
+	Go:     exportGo,
+}
+
+// Export renders a built request as a snippet that reproduces it with another tool. Credentials are redacted when
+// redacted is set, see redact.Request, the snippet then does not reproduce the request as is.
+func Export(request model.Request, format string, redacted bool) (string, error) {
+	exporter, ok := exporters[format]
+	if !ok {
+		return "", fmt.Errorf("unknown export format %s, must be one of following: %s", format, strings.Join(Formats, ", "))
+	}
+	if redacted {
+		request = redact.Request(request)
+	}
+	return exporter(request)
+}
+
+func exportCurl(request model.Request) (string, error) {
+	body, headers, err := bodyAndHeaders(request)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{fmt.Sprintf("curl -X %s %s", request.Method, shellQuote(request.Url))}
+	for _, name := range sortedHeaderNames(headers) {
+		parts = append(parts, fmt.Sprintf("-H %s", shellQuote(fmt.Sprintf("%s: %s", name, strings.Join(headers[name], ",")))))
+	}
+	if body != "" {
+		parts = append(parts, fmt.Sprintf("--data-raw %s", shellQuote(body)))
+	}
+	if request.Insecure {
+		parts = append(parts, "-k")
+	}
+	return strings.Join(parts, " \\\n  ") + "\n", nil
+}
+
+func exportHttpie(request model.Request) (string, error) {
+	body, headers, err := bodyAndHeaders(request)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{"http"}
+	if request.Insecure {
+		parts = append(parts, "--verify=no")
+	}
+	if body != "" {
+		parts = append(parts, fmt.Sprintf("--raw %s", shellQuote(body)))
+	}
+	parts = append(parts, fmt.Sprintf("%s %s", request.Method, shellQuote(request.Url)))
+	for _, name := range sortedHeaderNames(headers) {
+		parts = append(parts, shellQuote(fmt.Sprintf("%s:%s", name, strings.Join(headers[name], ","))))
+	}
+	return strings.Join(parts, " \\\n  ") + "\n", nil
+}
+
+func exportWget(request model.Request) (string, error) {
+	body, headers, err := bodyAndHeaders(request)
+	if err != nil {
+		return "", err
+	}
+
+	parts := []string{fmt.Sprintf("wget --method=%s", request.Method)}
+	for _, name := range sortedHeaderNames(headers) {
+		parts = append(parts, fmt.Sprintf("--header=%s", shellQuote(fmt.Sprintf("%s: %s", name, strings.Join(headers[name], ",")))))
+	}
+	if body != "" {
+		parts = append(parts, fmt.Sprintf("--body-data=%s", shellQuote(body)))
+	}
+	if request.Insecure {
+		parts = append(parts, "--no-check-certificate")
+	}
+	parts = append(parts, "--output-document=-", shellQuote(request.Url))
+	return strings.Join(parts, " \\\n  ") + "\n", nil
+}
+
+func exportGo(request model.Request) (string, error) {
+	body, headers, err := bodyAndHeaders(request)
+	if err != nil {
+		return "", err
+	}
+
+	imports := []string{"fmt", "io", "net/http"}
+	if body != "" {
+		imports = append(imports, "strings")
+	}
+	if request.Insecure {
+		imports = append(imports, "crypto/tls")
+	}
+	sort.Strings(imports)
+
+	var sb strings.Builder
+	sb.WriteString("package main\n\nimport (\n")
+	for _, i := range imports {
+		sb.WriteString(fmt.Sprintf("\t%q\n", i))
+	}
+	sb.WriteString(")\n\nfunc main() {\n")
+	if body != "" {
+		sb.WriteString(fmt.Sprintf("\tbody := strings.NewReader(%s)\n", goQuote(body)))
+		sb.WriteString(fmt.Sprintf("\treq, err := http.NewRequest(%q, %q, body)\n", request.Method, request.Url))
+	} else {
+		sb.WriteString(fmt.Sprintf("\treq, err := http.NewRequest(%q, %q, nil)\n", request.Method, request.Url))
+	}
+	sb.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, name := range sortedHeaderNames(headers) {
+		for _, value := range headers[name] {
+			sb.WriteString(fmt.Sprintf("\treq.Header.Add(%q, %q)\n", name, value))
+		}
+	}
+	if request.Insecure {
+		sb.WriteString("\n\tclient := &http.Client{\n")
+		sb.WriteString("\t\tTransport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},\n")
+		sb.WriteString("\t}\n")
+	} else {
+		sb.WriteString("\n\tclient := http.DefaultClient\n")
+	}
+	sb.WriteString("\tresp, err := client.Do(req)\n")
+	sb.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	sb.WriteString("\tdefer resp.Body.Close()\n\n")
+	sb.WriteString("\trespBody, err := io.ReadAll(resp.Body)\n")
+	sb.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	sb.WriteString("\tfmt.Println(resp.Status)\n")
+	sb.WriteString("\tfmt.Println(string(respBody))\n")
+	sb.WriteString("}\n")
+	return sb.String(), nil
+}
+
+// bodyAndHeaders serializes the request body the same way the client does:
+// strings are sent as is, other values are encoded as JSON.
+// Cookies are sent in a single header separated by semicolons, so repeated cookie headers are joined.
+func bodyAndHeaders(request model.Request) (string, model.Headers, error) {
+	headers := make(model.Headers)
+	for k, v := range request.Headers {
+		if strings.EqualFold(k, "Cookie") && len(v) > 1 {
+			v = model.HeaderValues{strings.Join(v, "; ")}
+		}
+		headers[k] = v
+	}
+
+	switch body := request.Body.(type) {
+	case nil:
+		return "", headers, nil
+	case string:
+		return body, headers, nil
+	case []byte:
+		return string(body), headers, nil
+	default:
+		b, err := json.Marshal(body)
+		if err != nil {
+			return "", nil, err
+		}
+		if !hasHeader(headers, "Content-Type") {
+			headers["Content-Type"] = model.HeaderValues{"application/json"}
+		}
+		return string(b), headers, nil
+	}
+}
+
+func hasHeader(headers model.Headers, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedHeaderNames(headers model.Headers) []string {
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func goQuote(s string) string {
+	// raw strings can not hold backticks and drop carriage returns
+	if strings.ContainsAny(s, "`\r") {
+		return fmt.Sprintf("%q", s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,347 @@
+package exporter
+
+import (
+	"goful/core/model"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestExportCurl(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "POST",
+		Headers: model.Headers{
+			"X-Foo-Bar": {"SomeValue"},
+			"Accept":    {"application/json"},
+		},
+		Body: `{"name": "Jane's"}`,
+	}
+
+	wanted := `curl -X POST 'http://foobar.com/api' \
+  -H 'Accept: application/json' \
+  -H 'X-Foo-Bar: SomeValue' \
+  --data-raw '{"name": "Jane'\''s"}'
+`
+
+	exported, err := Export(request, Curl, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
+
+func TestExportJoinsCookies(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "GET",
+		Headers: model.Headers{
+			"Cookie": {"session=abc", "theme=dark"},
+		},
+	}
+
+	wanted := `curl -X GET 'http://foobar.com/api' \
+  -H 'Cookie: session=abc; theme=dark'
+`
+
+	exported, err := Export(request, Curl, false)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+
+	exported, err = Export(request, Go, false)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if !strings.Contains(exported, `req.Header.Add("Cookie", "session=abc; theme=dark")`) {
+		t.Errorf("got\n%v\nwanted a single Cookie header", exported)
+	}
+}
+
+func TestExportRedactsCredentials(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "GET",
+		Headers: model.Headers{
+			"Authorization": {"Bearer abc.def"},
+		},
+	}
+
+	wanted := `curl -X GET 'http://foobar.com/api' \
+  -H 'Authorization: ****'
+`
+
+	exported, err := Export(request, Curl, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+
+	wantedUnredacted := `curl -X GET 'http://foobar.com/api' \
+  -H 'Authorization: Bearer abc.def'
+`
+	exported, err = Export(request, Curl, false)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wantedUnredacted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wantedUnredacted)
+	}
+}
+
+func TestExportHttpieWithStarlarkBody(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "PUT",
+		Body: map[string]interface{}{
+			"id":   big.NewInt(1),
+			"name": "Jane",
+		},
+		Insecure: true,
+	}
+
+	wanted := `http \
+  --verify=no \
+  --raw '{"id":1,"name":"Jane"}' \
+  PUT 'http://foobar.com/api' \
+  'Content-Type:application/json'
+`
+
+	exported, err := Export(request, Httpie, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
+
+func TestExportWget(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "GET",
+		Headers: model.Headers{
+			"X-Foo-Bar": {"SomeValue"},
+		},
+	}
+
+	wanted := `wget --method=GET \
+  --header='X-Foo-Bar: SomeValue' \
+  --output-document=- \
+  'http://foobar.com/api'
+`
+
+	exported, err := Export(request, Wget, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
+
+func TestExportGo(t *testing.T) {
+	request := model.Request{
+		Url:    "http://foobar.com/api",
+		Method: "POST",
+		Headers: model.Headers{
+			"X-Foos": {"Bar1", "Bar2"},
+		},
+		Body: "id=1",
+	}
+
+	wanted := "package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"strings\"\n)\n\n" +
+		"func main() {\n" +
+		"\tbody := strings.NewReader(`id=1`)\n" +
+		"\treq, err := http.NewRequest(\"POST\", \"http://foobar.com/api\", body)\n" +
+		"\tif err != nil {\n\t\tpanic(err)\n\t}\n" +
+		"\treq.Header.Add(\"X-Foos\", \"Bar1\")\n" +
+		"\treq.Header.Add(\"X-Foos\", \"Bar2\")\n\n" +
+		"\tclient := http.DefaultClient\n" +
+		"\tresp, err := client.Do(req)\n" +
+		"\tif err != nil {\n\t\tpanic(err)\n\t}\n" +
+		"\tdefer resp.Body.Close()\n\n" +
+		"\trespBody, err := io.ReadAll(resp.Body)\n" +
+		"\tif err != nil {\n\t\tpanic(err)\n\t}\n" +
+		"\tfmt.Println(resp.Status)\n" +
+		"\tfmt.Println(string(respBody))\n" +
+		"}\n"
+
+	exported, err := Export(request, Go, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	_, err := Export(model.Request{}, "powershell", true)
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
+
+func TestSprintHttpFile(t *testing.T) {
+	molds := []model.RequestMold{
+		{
+			Yaml: &model.YamlRequest{
+				Name:   "Create user",
+				Url:    "{base_url}/users",
+				Method: "POST",
+				Headers: model.Headers{
+					"Authorization": {"Bearer {token}"},
+					"Content-Type":  {"application/json"},
+				},
+				Body: "{\n  \"name\": \"Jane\"\n}\n",
+			},
+		},
+		{
+			Starlark: &model.StarlarkRequest{
+				Script: "\"\"\"\nmeta:name: Starlark request\n\"\"\"\n",
+			},
+		},
+		{
+			Yaml: &model.YamlRequest{
+				Name:   "List users",
+				Url:    "{base_url}/users",
+				Method: "GET",
+				Query: model.Query{
+					"name":  {"Jane Doe"},
+					"limit": {"{limit}"},
+				},
+			},
+		},
+	}
+
+	wanted := `@base_url = https://example.com
+@token = ****
+
+### Create user
+POST {{base_url}}/users
+Authorization: Bearer {{token}}
+Content-Type: application/json
+
+{
+  "name": "Jane"
+}
+
+### List users
+GET {{base_url}}/users?limit={{limit}}&name=Jane+Doe
+`
+
+	exported, skipped, err := SprintHttpFile(molds, map[string]string{"base_url": "https://example.com", "token": "abc"}, model.Defaults{}, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+	if len(skipped) != 1 || skipped[0] != "Starlark request" {
+		t.Errorf("got %v, wanted %v", skipped, []string{"Starlark request"})
+	}
+}
+
+func TestSprintHttpFileUnredacted(t *testing.T) {
+	molds := []model.RequestMold{
+		{
+			Yaml: &model.YamlRequest{
+				Name:   "List users",
+				Url:    "{base_url}/users",
+				Method: "GET",
+				Headers: model.Headers{
+					"Authorization": {"Bearer abc.def"},
+				},
+			},
+		},
+	}
+
+	wanted := `@base_url = https://example.com
+@token = abc.def
+
+### List users
+GET {{base_url}}/users
+Authorization: Bearer abc.def
+`
+
+	exported, _, err := SprintHttpFile(molds, map[string]string{"base_url": "https://example.com", "token": "abc.def"}, model.Defaults{}, false)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
+
+func TestSprintHttpFileWithDefaults(t *testing.T) {
+	molds := []model.RequestMold{
+		{
+			Yaml: &model.YamlRequest{
+				Name:   "Get user",
+				Url:    "/users/{id}?q=jane",
+				Method: "GET",
+				Headers: model.Headers{
+					"accept": {"text/plain"},
+				},
+				Query: model.Query{"verbose": {"true"}},
+			},
+		},
+		{
+			Yaml: &model.YamlRequest{
+				Name:   "Health",
+				Url:    "https://status.example.com/health",
+				Method: "GET",
+			},
+		},
+	}
+	defaults := model.Defaults{
+		BaseUrl: "https://{host}/api/",
+		Headers: model.Headers{
+			"Accept":        {"application/json"},
+			"Authorization": {"Bearer {token}"},
+		},
+		Query: model.Query{"q": {"all"}, "verbose": {"false"}, "api-version": {"2"}},
+	}
+
+	wanted := `@host = example.com
+@token = ****
+@base_url = https://{{host}}/api
+
+### Get user
+GET {{base_url}}/users/{{id}}?q=jane&api-version=2&verbose=true
+Authorization: Bearer {{token}}
+accept: text/plain
+
+### Health
+GET https://status.example.com/health?api-version=2&q=all&verbose=false
+Accept: application/json
+Authorization: Bearer {{token}}
+`
+
+	exported, _, err := SprintHttpFile(molds, map[string]string{"host": "example.com", "token": "abc"}, defaults, true)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if exported != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", exported, wanted)
+	}
+}
@@ -0,0 +1,167 @@
+package exporter
+
+import (
+	"fmt"
+	"goful/core/client/validator"
+	"goful/core/model"
+	"goful/core/redact"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const Http = "http"
+
+var templateVariablePattern = regexp.MustCompile(`{([A-Za-z0-9_.]+)}`)
+
+// SprintHttpFile renders request molds as a VS Code REST Client and JetBrains compatible .http file.
+// Unlike Export it keeps template variables, converted to {{var}}, so IDE clients resolve them from
+// the @var declarations rendered from variables. The base url of the defaults is declared as @base_url and prepended
+// to relative urls, default headers and query parameters are added to every request that does not set them.
+// Starlark requests can not be expressed and are skipped, their names are returned.
+// Values of sensitive variables and headers are redacted when redacted is set.
+func SprintHttpFile(molds []model.RequestMold, variables map[string]string, defaults model.Defaults, redacted bool) (string, []string, error) {
+	var sb strings.Builder
+	var skipped []string
+
+	keys := make([]string, 0, len(variables))
+	for k := range variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := toDoubleBraces(variables[k])
+		if redacted && redact.IsSensitiveVariable(k) {
+			value = redact.Mask
+		}
+		sb.WriteString(fmt.Sprintf("@%s = %s\n", k, value))
+	}
+
+	// a base_url variable is usually what the base url of the defaults refers to, it is not redeclared
+	baseUrlVariable := "base_url"
+	if _, ok := variables[baseUrlVariable]; ok {
+		baseUrlVariable = "default_base_url"
+	}
+	if defaults.BaseUrl != "" {
+		sb.WriteString(fmt.Sprintf("@%s = %s\n", baseUrlVariable, toDoubleBraces(strings.TrimRight(defaults.BaseUrl, "/"))))
+	}
+
+	for _, mold := range molds {
+		if mold.Yaml == nil {
+			skipped = append(skipped, mold.Name())
+			continue
+		}
+		yamlRequest := mold.Yaml
+		body, headers, err := bodyAndHeaders(model.Request{Headers: withDefaultHeaders(yamlRequest.Headers, defaults.Headers), Body: yamlRequest.Body})
+		if err != nil {
+			return "", skipped, err
+		}
+
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n", yamlRequest.Name))
+		requestUrl := yamlRequest.Url
+		if defaults.BaseUrl != "" && !validator.IsAbsoluteUrl(requestUrl) {
+			requestUrl = joinUrl("{"+baseUrlVariable+"}", requestUrl)
+		}
+		query := withDefaultQuery(requestUrl, yamlRequest.Query, defaults.Query)
+		sb.WriteString(fmt.Sprintf("%s %s\n", yamlRequest.Method, httpFileUrl(requestUrl, query)))
+		for _, name := range sortedHeaderNames(headers) {
+			value := strings.Join(headers[name], ",")
+			// template variables are kept, they refer to the redacted @var declarations
+			if redacted && redact.IsSensitiveHeader(name) && !templateVariablePattern.MatchString(value) {
+				value = redact.Mask
+			}
+			sb.WriteString(fmt.Sprintf("%s: %s\n", name, toDoubleBraces(value)))
+		}
+		if body = strings.TrimSpace(body); body != "" {
+			sb.WriteString("\n")
+			sb.WriteString(body)
+			sb.WriteString("\n")
+		}
+	}
+
+	if !redacted {
+		return sb.String(), skipped, nil
+	}
+	return redact.String(sb.String()), skipped, nil
+}
+
+func joinUrl(baseUrl string, path string) string {
+	if path == "" {
+		return baseUrl
+	}
+	return baseUrl + "/" + strings.TrimLeft(path, "/")
+}
+
+// withDefaultHeaders adds the default headers the request does not set, header names are matched case-insensitively
+func withDefaultHeaders(headers model.Headers, defaultHeaders model.Headers) model.Headers {
+	merged := make(model.Headers, len(headers)+len(defaultHeaders))
+	for name, values := range defaultHeaders {
+		if !hasHeader(headers, name) {
+			merged[name] = values
+		}
+	}
+	for name, values := range headers {
+		merged[name] = values
+	}
+	return merged
+}
+
+// withDefaultQuery adds the default query parameters that neither the query nor the url of the request have
+func withDefaultQuery(rawUrl string, query model.Query, defaultQuery model.Query) model.Query {
+	_, rawQuery, _ := strings.Cut(strings.SplitN(rawUrl, "#", 2)[0], "?")
+	existing, _ := url.ParseQuery(rawQuery)
+	merged := make(model.Query, len(query)+len(defaultQuery))
+	for name, values := range defaultQuery {
+		if !existing.Has(name) {
+			merged[name] = values
+		}
+	}
+	for name, values := range query {
+		merged[name] = values
+	}
+	return merged
+}
+
+// httpFileUrl appends the query to the url, the values are url encoded except for their template variables
+func httpFileUrl(rawUrl string, query model.Query) string {
+	var params []string
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range query[name] {
+			params = append(params, url.QueryEscape(name)+"="+escapeQueryValue(v))
+		}
+	}
+	rawUrl = toDoubleBraces(rawUrl)
+	if len(params) == 0 {
+		return rawUrl
+	}
+	separator := "?"
+	if strings.Contains(rawUrl, "?") {
+		separator = "&"
+	}
+	return rawUrl + separator + strings.Join(params, "&")
+}
+
+func escapeQueryValue(v string) string {
+	var sb strings.Builder
+	last := 0
+	for _, match := range templateVariablePattern.FindAllStringIndex(v, -1) {
+		sb.WriteString(url.QueryEscape(v[last:match[0]]))
+		sb.WriteString(toDoubleBraces(v[match[0]:match[1]]))
+		last = match[1]
+	}
+	sb.WriteString(url.QueryEscape(v[last:]))
+	return sb.String()
+}
+
+func toDoubleBraces(s string) string {
+	return templateVariablePattern.ReplaceAllString(s, "{{$1}}")
+}
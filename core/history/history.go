@@ -0,0 +1,338 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"goful/core/redact"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	historyDir  = ".goful"
+	historyFile = "history.jsonl"
+)
+
+// Entry is a single executed request with the response it received.
+// Response is nil when the request failed before a response was received, Error tells why.
+type Entry struct {
+	Id       int             `json:"id"`
+	Name     string          `json:"name"`
+	Profile  string          `json:"profile"`
+	Time     time.Time       `json:"time"`
+	Duration time.Duration   `json:"duration"`
+	Request  model.Request   `json:"request"`
+	Response *model.Response `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// Redacted tells that credentials were redacted from Request when it was recorded
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// NewEntry describes a request executed at started. err is the error returned by the client, if any.
+func NewEntry(name string, profile string, request model.Request, response *model.Response, err error, started time.Time) Entry {
+	entry := Entry{
+		Name:     name,
+		Profile:  profile,
+		Time:     started,
+		Duration: time.Since(started),
+		Request:  request,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Response = response
+	if response != nil && response.Duration > 0 {
+		entry.Duration = response.Duration
+	}
+	return entry
+}
+
+// Label identifies the entry for humans, e.g. "#12 Create user (staging)".
+func (e Entry) Label() string {
+	label := fmt.Sprintf("#%d", e.Id)
+	if e.Name != "" {
+		label += " " + e.Name
+	}
+	if e.Profile != "" {
+		label += fmt.Sprintf(" (%s)", e.Profile)
+	}
+	return label
+}
+
+// DefaultMaxEntries is how many entries the history keeps unless history.max_entries is configured
+const DefaultMaxEntries = 1000
+
+// Store keeps the history as a JSON lines file, one entry per line, oldest first.
+type Store struct {
+	path string
+	// maxEntries is how many of the latest entries are kept, all are kept when it is not positive
+	maxEntries int
+}
+
+// appends are serialized so that concurrently run requests get unique ids
+var appendMutex sync.Mutex
+
+var ErrNotFound = errors.New("history entry not found")
+
+// NewStore returns the history store of a workspace, which keeps as many entries as history.max_entries tells.
+func NewStore(root string) Store {
+	return Store{
+		path:       filepath.Join(root, historyDir, historyFile),
+		maxEntries: viper.GetInt("history.max_entries"),
+	}
+}
+
+// WithMaxEntries returns the store keeping the latest maxEntries entries, or all when maxEntries is not positive
+func (s Store) WithMaxEntries(maxEntries int) Store {
+	s.maxEntries = maxEntries
+	return s
+}
+
+func (s Store) Path() string {
+	return s.path
+}
+
+// Append assigns the next id to the entry and writes it at the end of the history. The oldest entries are removed
+// once the history holds more than the maximum number of entries.
+func (s Store) Append(entry Entry) (Entry, error) {
+	appendMutex.Lock()
+	defer appendMutex.Unlock()
+
+	lastId, err := s.lastId()
+	if err != nil {
+		return entry, err
+	}
+	entry.Id = lastId + 1
+
+	line, err := json.Marshal(redactEntry(entry))
+	if err != nil {
+		return entry, err
+	}
+	// bodies of Starlark requests are structured, they are redacted as JSON
+	line = redact.Bytes(line)
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0755)
+	if err != nil {
+		return entry, err
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return entry, err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		return entry, err
+	}
+	return entry, s.prune(entry.Id)
+}
+
+// Record appends the entry into the history of the workspace in root and returns it with its id.
+// Failing to do so is not fatal, the entry is returned as is.
+func Record(root string, entry Entry) Entry {
+	store := NewStore(root)
+	recorded, err := store.Append(entry)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to record request %s to history %s", entry.Name, store.Path())
+		return entry
+	}
+	log.Debug().Msgf("Recorded request %s to history as #%d", recorded.Name, recorded.Id)
+	return recorded
+}
+
+// lastId reads the id of the last entry from the end of the history, so that appending does not read every entry
+func (s Store) lastId() (int, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	// the last line is read backwards in chunks, responses make lines of any length
+	const chunkSize = 4096
+	var tail []byte
+	offset := info.Size()
+	for {
+		trimmed := bytes.TrimRight(tail, " \t\r\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 || offset == 0 {
+			return parseId(trimmed[i+1:])
+		}
+		n := min(chunkSize, offset)
+		offset -= n
+		chunk := make([]byte, n)
+		_, err = file.ReadAt(chunk, offset)
+		if err != nil {
+			return 0, err
+		}
+		tail = append(chunk, tail...)
+	}
+}
+
+// prune removes the oldest entries when there are more than the maximum number of entries. The history is rewritten
+// only once it exceeds the maximum by a tenth, so that it is not rewritten on every append.
+func (s Store) prune(lastId int) error {
+	if s.maxEntries <= 0 {
+		return nil
+	}
+	firstId, err := s.firstId()
+	if err != nil {
+		return err
+	}
+	if lastId-firstId+1 <= s.maxEntries+s.maxEntries/10 {
+		return nil
+	}
+	keepFrom := lastId - s.maxEntries + 1
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	pruned, err := os.CreateTemp(filepath.Dir(s.path), historyFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(pruned.Name())
+	defer pruned.Close()
+
+	reader := bufio.NewReader(file)
+	writer := bufio.NewWriter(pruned)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			id, idErr := parseId(trimmed)
+			if idErr != nil {
+				return idErr
+			}
+			if id >= keepFrom {
+				writer.Write(append(trimmed, '\n'))
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+	if err = pruned.Close(); err != nil {
+		return err
+	}
+	return os.Rename(pruned.Name(), s.path)
+}
+
+// firstId reads the id of the first entry of the history
+func (s Store) firstId() (int, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			return parseId(trimmed)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
+
+// parseId decodes only the id of an entry, 0 for an empty line
+func parseId(line []byte) (int, error) {
+	if len(bytes.TrimSpace(line)) == 0 {
+		return 0, nil
+	}
+	var entry struct {
+		Id int `json:"id"`
+	}
+	err := json.Unmarshal(line, &entry)
+	return entry.Id, err
+}
+
+// redactEntry redacts credentials from the entry before it is written, see redact.Request. A redacted request
+// can not be sent again as is, it is marked as Redacted.
+func redactEntry(entry Entry) Entry {
+	request := redact.Request(entry.Request)
+	entry.Redacted = !reflect.DeepEqual(request, entry.Request)
+	entry.Request = request
+	if entry.Response != nil {
+		response := redact.Response(*entry.Response)
+		entry.Response = &response
+	}
+	entry.Error = redact.String(entry.Error)
+	return entry
+}
+
+// ReadAll returns all entries, oldest first. A missing history is empty.
+func (s Store) ReadAll() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := []Entry{}
+	// responses easily exceed the line limit of bufio.Scanner
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			var entry Entry
+			if jsonErr := json.Unmarshal(line, &entry); jsonErr != nil {
+				return nil, fmt.Errorf("%s:%d: %w", s.path, lineNumber, jsonErr)
+			}
+			entries = append(entries, entry)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Find returns the entry with the given id.
+func (s Store) Find(id int) (Entry, error) {
+	entries, err := s.ReadAll()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, entry := range entries {
+		if entry.Id == id {
+			return entry, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+}
@@ -0,0 +1,182 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"goful/core/model"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAppendAndReadAll(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if len(entries) != 0 {
+		t.Errorf("got %d, wanted %d", len(entries), 0)
+	}
+
+	started := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	request := model.Request{
+		Url:    "https://foobar.com/users",
+		Method: "POST",
+		Headers: model.Headers{
+			"Content-Type": {"application/json"},
+		},
+		Body: map[string]interface{}{"name": "Jane"},
+	}
+	response := &model.Response{
+		Headers:    model.Headers{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"id":1}`),
+		Status:     "201 Created",
+		StatusCode: 201,
+		Proto:      "HTTP/1.1",
+		Size:       8,
+		ReceivedAt: started.Add(120 * time.Millisecond),
+		Duration:   120 * time.Millisecond,
+	}
+
+	first, err := store.Append(NewEntry("Create user", "default", request, response, nil, started))
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	second, err := store.Append(NewEntry("Create user", "staging", request, nil, errors.New("connection refused"), started))
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if first.Id != 1 || second.Id != 2 {
+		t.Errorf("got ids %d and %d, wanted %d and %d", first.Id, second.Id, 1, 2)
+	}
+	if first.Duration != 120*time.Millisecond {
+		t.Errorf("got %v, wanted %v", first.Duration, 120*time.Millisecond)
+	}
+
+	entries, err = store.ReadAll()
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wanted := []Entry{first, second}
+	if !cmp.Equal(entries, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", entries, wanted)
+	}
+
+	found, err := store.Find(2)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if found.Response != nil || found.Error != "connection refused" {
+		t.Errorf("got\n%v\nwanted error %s", found, "connection refused")
+	}
+
+	_, err = store.Find(3)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, wanted %v", err, ErrNotFound)
+	}
+}
+
+func TestReadAllWithInvalidLine(t *testing.T) {
+	store := NewStore(t.TempDir())
+	_, err := store.Append(Entry{Name: "foo"})
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	file, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{not json\n")
+	file.Close()
+
+	_, err = store.ReadAll()
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
+
+func TestAppendRedactsCredentials(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	request := model.Request{
+		Url:    "https://foobar.com/users",
+		Method: "GET",
+		Headers: model.Headers{
+			"Authorization": {"Basic amFuZTpzZWNyZXQ="},
+			"Accept":        {"application/json"},
+		},
+	}
+	response := &model.Response{
+		Headers:    model.Headers{"Set-Cookie": {"session=abcdef123456"}},
+		Body:       []byte(`{"note":"Bearer abcdef123456"}`),
+		Status:     "200 OK",
+		StatusCode: 200,
+	}
+
+	recorded, err := store.Append(NewEntry("List users", "default", request, response, nil, time.Now()))
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if recorded.Request.Headers["Authorization"][0] != "Basic amFuZTpzZWNyZXQ=" {
+		t.Errorf("expected the returned entry to keep the request as is, got %v", recorded.Request.Headers)
+	}
+
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	entry := entries[0]
+	if !entry.Redacted {
+		t.Errorf("expected entry to be marked as redacted")
+	}
+	wantedHeaders := model.Headers{"Authorization": {"****"}, "Accept": {"application/json"}}
+	if !cmp.Equal(entry.Request.Headers, wantedHeaders) {
+		t.Errorf("got\n%v\nwanted\n%v", entry.Request.Headers, wantedHeaders)
+	}
+	if entry.Response.Headers["Set-Cookie"][0] != "****" {
+		t.Errorf("got\n%v\nwanted\n%v", entry.Response.Headers["Set-Cookie"][0], "****")
+	}
+	if string(entry.Response.Body) != `{"note":"Bearer ****"}` {
+		t.Errorf("got\n%v\nwanted\n%v", string(entry.Response.Body), `{"note":"Bearer ****"}`)
+	}
+}
+
+func TestAppendKeepsMaxEntries(t *testing.T) {
+	store := NewStore(t.TempDir()).WithMaxEntries(10)
+	body := bytes.Repeat([]byte("x"), 10000)
+
+	for i := 1; i <= 25; i++ {
+		entry, err := store.Append(Entry{Name: "foo", Response: &model.Response{Body: body}})
+		if err != nil {
+			t.Errorf("did not expect error %v", err)
+			return
+		}
+		if entry.Id != i {
+			t.Errorf("got id %d, wanted %d", entry.Id, i)
+		}
+	}
+
+	entries, err := store.ReadAll()
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	// the history is pruned to 10 entries once it holds more than 11, the last time when #24 was appended
+	if len(entries) != 11 || entries[0].Id != 15 || entries[len(entries)-1].Id != 25 {
+		t.Errorf("got %d entries from #%d to #%d, wanted 11 from #15 to #25", len(entries), entries[0].Id, entries[len(entries)-1].Id)
+	}
+}
@@ -0,0 +1,417 @@
+package importer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"goful/core/client/validator"
+	"goful/core/model"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// curl options that take no argument and have no meaning for a stored request
+var ignoredCurlFlags = []string{
+	"-s", "--silent",
+	"-S", "--show-error",
+	"-v", "--verbose",
+	"-i", "--include",
+	"-L", "--location",
+	"-g", "--globoff",
+	"--compressed",
+	"--http1.1",
+	"--http2",
+}
+
+// curl options that take no argument, they may be combined like -Lk
+var curlFlags = append([]string{"-k", "-G", "-I"}, ignoredCurlFlags...)
+
+func ParseCurl(command string) (*model.YamlRequest, error) {
+	args, err := splitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCurlArgs(args)
+}
+
+func ParseCurlArgs(args []string) (*model.YamlRequest, error) {
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil, errors.New("curl command is empty")
+	}
+
+	yamlRequest := &model.YamlRequest{
+		Headers: make(model.Headers),
+	}
+
+	var data []string
+	var form []string
+	var cookies []string
+	var rawUrl string
+	useGet := false
+
+	// combined options are split into the args while parsing
+	args = slices.Clone(args)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") {
+			if rawUrl != "" {
+				return nil, fmt.Errorf("unexpected argument %s, url is already set to %s", arg, rawUrl)
+			}
+			rawUrl = arg
+			continue
+		}
+
+		name, value, hasValue := splitCurlOption(arg)
+		if hasValue && !strings.HasPrefix(name, "--") && slices.Contains(curlFlags, name) {
+			args = slices.Insert(args, i+1, "-"+value)
+			value, hasValue = "", false
+		}
+
+		takeValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("option %s requires a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			yamlRequest.Method = strings.ToUpper(v)
+		case "-H", "--header":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			headerName, headerValue, found := strings.Cut(v, ":")
+			if !found {
+				return nil, fmt.Errorf("invalid header %s", v)
+			}
+			addHeader(yamlRequest.Headers, strings.TrimSpace(headerName), strings.TrimSpace(headerValue))
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(v, "@") && name != "--data-raw" {
+				return nil, fmt.Errorf("reading data from file %s is not supported", v[1:])
+			}
+			data = append(data, v)
+		case "--data-urlencode":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, urlEncodeCurlData(v))
+		case "-F", "--form":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			form = append(form, v)
+		case "-u", "--user":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			addHeader(yamlRequest.Headers, "Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(v)))
+		case "-A", "--user-agent":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			addHeader(yamlRequest.Headers, "User-Agent", v)
+		case "-e", "--referer":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			addHeader(yamlRequest.Headers, "Referer", v)
+		case "-b", "--cookie":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			cookies = append(cookies, v)
+		case "--url":
+			v, err := takeValue()
+			if err != nil {
+				return nil, err
+			}
+			rawUrl = v
+		case "-k", "--insecure":
+			yamlRequest.Insecure = true
+		case "-G", "--get":
+			useGet = true
+		case "-I", "--head":
+			yamlRequest.Method = http.MethodHead
+		default:
+			// the Go http client already negotiates and decodes gzip, so --compressed needs no special care
+			if !slices.Contains(ignoredCurlFlags, name) {
+				return nil, fmt.Errorf("unsupported curl option %s", name)
+			}
+		}
+	}
+
+	joinCookies(yamlRequest.Headers, cookies)
+
+	if rawUrl == "" {
+		return nil, errors.New("curl command has no url")
+	}
+	if !strings.Contains(rawUrl, "://") {
+		rawUrl = "http://" + rawUrl
+	}
+
+	if len(data) > 0 && len(form) > 0 {
+		return nil, errors.New("options --data and --form can not be used together")
+	}
+
+	if len(data) > 0 {
+		joined := strings.Join(data, "&")
+		if useGet {
+			separator := "?"
+			if strings.Contains(rawUrl, "?") {
+				separator = "&"
+			}
+			rawUrl = rawUrl + separator + joined
+		} else {
+			yamlRequest.Body = joined
+			if !hasHeader(yamlRequest.Headers, "Content-Type") {
+				addHeader(yamlRequest.Headers, "Content-Type", "application/x-www-form-urlencoded")
+			}
+		}
+	}
+
+	if len(form) > 0 {
+		body, contentType, err := multipartCurlForm(form)
+		if err != nil {
+			return nil, err
+		}
+		yamlRequest.Body = body
+		yamlRequest.Headers["Content-Type"] = model.HeaderValues{contentType}
+	}
+
+	if yamlRequest.Method == "" {
+		if yamlRequest.Body != nil {
+			yamlRequest.Method = validator.DefaultBodifulMethod
+		} else {
+			yamlRequest.Method = validator.DefaultBodilessMethod
+		}
+	}
+
+	yamlRequest.Url = rawUrl
+	if len(yamlRequest.Headers) == 0 {
+		yamlRequest.Headers = nil
+	}
+
+	return yamlRequest, nil
+}
+
+// SuggestName returns a name for a request derived from its method and url path,
+// e.g. "GET users" for "GET https://example.com/api/users?page=2".
+func SuggestName(yamlRequest *model.YamlRequest) string {
+	name := yamlRequest.Method
+	u, err := url.Parse(yamlRequest.Url)
+	if err != nil {
+		return name
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	last := segments[len(segments)-1]
+	if last == "" {
+		last = u.Hostname()
+	}
+	return sanitizeFilename(fmt.Sprintf("%s %s", name, last))
+}
+
+func splitCurlOption(arg string) (string, string, bool) {
+	if strings.HasPrefix(arg, "--") {
+		name, value, found := strings.Cut(arg, "=")
+		return name, value, found
+	}
+	// short options may be glued to their value, e.g. -XPOST
+	if len(arg) > 2 {
+		return arg[:2], arg[2:], true
+	}
+	return arg, "", false
+}
+
+func urlEncodeCurlData(v string) string {
+	name, content, found := strings.Cut(v, "=")
+	if !found {
+		return url.QueryEscape(v)
+	}
+	if name == "" {
+		return url.QueryEscape(content)
+	}
+	return name + "=" + url.QueryEscape(content)
+}
+
+func multipartCurlForm(fields []string) (string, string, error) {
+	var formFields []formField
+	for _, field := range fields {
+		name, value, found := strings.Cut(field, "=")
+		if !found {
+			return "", "", fmt.Errorf("invalid form field %s", field)
+		}
+		if strings.HasPrefix(value, "@") || strings.HasPrefix(value, "<") {
+			return "", "", fmt.Errorf("file upload in form field %s is not supported", name)
+		}
+		formFields = append(formFields, formField{name, value})
+	}
+	body, contentType := multipartBody(formFields)
+	return body, contentType, nil
+}
+
+// joinCookies merges cookies into the Cookie header. Cookies are sent in a single header separated by semicolons,
+// not as repeated headers, which would be joined with commas.
+func joinCookies(headers model.Headers, cookies []string) {
+	name := "Cookie"
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			name = k
+			cookies = append(slices.Clone(v), cookies...)
+		}
+	}
+	if len(cookies) > 0 {
+		headers[name] = model.HeaderValues{strings.Join(cookies, "; ")}
+	}
+}
+
+func addHeader(headers model.Headers, name string, value string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			headers[k] = append(headers[k], value)
+			return
+		}
+	}
+	headers[name] = model.HeaderValues{value}
+}
+
+func hasHeader(headers model.Headers, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCommandLine splits a shell command line into arguments the way a POSIX shell would,
+// supporting single and double quotes, $'...' strings and backslash line continuations.
+func splitCommandLine(command string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	runes := []rune(command)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				if runes[i] == '\n' || runes[i] == '\r' {
+					if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+						i++
+					}
+					continue
+				}
+				current.WriteRune(runes[i])
+				inArg = true
+			}
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			inArg = true
+			i = end
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			value, end, err := readAnsiCString(runes, i+2)
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(value)
+			inArg = true
+			i = end
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errors.New("unterminated double quote")
+			}
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+func readAnsiCString(runes []rune, from int) (string, int, error) {
+	var sb strings.Builder
+	for i := from; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\'' {
+			return sb.String(), i, nil
+		}
+		if r == '\\' && i+1 < len(runes) {
+			i++
+			switch runes[i] {
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			case 'r':
+				sb.WriteRune('\r')
+			default:
+				sb.WriteRune(runes[i])
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return "", 0, errors.New("unterminated $' quote")
+}
@@ -0,0 +1,140 @@
+package importer
+
+import (
+	"goful/core/model"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCurlFromDevtools(t *testing.T) {
+	command := `curl 'https://api.example.com/v1/users?page=2' \
+  -H 'accept: application/json' \
+  -H 'content-type: application/json' \
+  --data-raw '{"name":"Jane"}' \
+  --compressed`
+
+	wantedRequest := &model.YamlRequest{
+		Url:    "https://api.example.com/v1/users?page=2",
+		Method: "POST",
+		Headers: model.Headers{
+			"accept":       {"application/json"},
+			"content-type": {"application/json"},
+		},
+		Body: `{"name":"Jane"}`,
+	}
+
+	request, err := ParseCurl(command)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if !cmp.Equal(request, wantedRequest) {
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
+	}
+}
+
+func TestParseCurlWithUserAndInsecure(t *testing.T) {
+	command := `curl -X PUT -u "jane:secret" -k "https://localhost:8443/api" -d "a=1" -d "b=2"`
+
+	wantedRequest := &model.YamlRequest{
+		Url:    "https://localhost:8443/api",
+		Method: "PUT",
+		Headers: model.Headers{
+			"Authorization": {"Basic amFuZTpzZWNyZXQ="},
+			"Content-Type":  {"application/x-www-form-urlencoded"},
+		},
+		Body:     "a=1&b=2",
+		Insecure: true,
+	}
+
+	request, err := ParseCurl(command)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if !cmp.Equal(request, wantedRequest) {
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
+	}
+}
+
+func TestParseCurlWithForm(t *testing.T) {
+	request, err := ParseCurl(`curl -F name=Jane -F "role=admin" http://foobar.com/upload`)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if request.Method != "POST" {
+		t.Errorf("got %s, wanted %s", request.Method, "POST")
+	}
+	wantedContentType := "multipart/form-data; boundary=------------------------goful"
+	if request.Headers["Content-Type"][0] != wantedContentType {
+		t.Errorf("got %s, wanted %s", request.Headers["Content-Type"][0], wantedContentType)
+	}
+	wantedBody := "--------------------------goful\r\nContent-Disposition: form-data; name=\"name\"\r\n\r\nJane\r\n" +
+		"--------------------------goful\r\nContent-Disposition: form-data; name=\"role\"\r\n\r\nadmin\r\n" +
+		"--------------------------goful--\r\n"
+	if request.Body != wantedBody {
+		t.Errorf("got\n%q\nwanted\n%q", request.Body, wantedBody)
+	}
+}
+
+func TestParseCurlWithUnsupportedOption(t *testing.T) {
+	_, err := ParseCurl(`curl --proxy http://proxy:8080 http://foobar.com`)
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
+
+func TestMarshalYamlRequest(t *testing.T) {
+	yamlRequest := &model.YamlRequest{
+		Name:   "get users",
+		Url:    "https://api.example.com/v1/users",
+		Method: "GET",
+		Headers: model.Headers{
+			"Accept": {"application/json"},
+		},
+	}
+
+	wanted := `name: get users
+url: https://api.example.com/v1/users
+method: GET
+headers:
+  Accept: application/json
+`
+
+	raw, err := MarshalYamlRequest(yamlRequest)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if raw != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", raw, wanted)
+	}
+}
+
+func TestParseCurlWithCombinedFlagsAndCookies(t *testing.T) {
+	command := `curl -sLk -XPOST -b "a=1" --cookie "b=2" -H "Cookie: c=3" https://localhost:8443/api`
+
+	wantedRequest := &model.YamlRequest{
+		Url:    "https://localhost:8443/api",
+		Method: "POST",
+		Headers: model.Headers{
+			"Cookie": {"c=3; a=1; b=2"},
+		},
+		Insecure: true,
+	}
+
+	request, err := ParseCurl(command)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	if !cmp.Equal(request, wantedRequest) {
+		t.Errorf("got\n%v\nwanted\n%v\n", request, wantedRequest)
+	}
+}
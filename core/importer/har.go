@@ -0,0 +1,199 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// volatileHarHeaders are recorded by browsers but are either set by the client itself or tied to the
+// original session, replaying them would only produce noise
+var volatileHarHeaders = []string{
+	"accept-encoding",
+	"connection",
+	"content-length",
+	"host",
+	"if-modified-since",
+	"if-none-match",
+	"priority",
+	"upgrade-insecure-requests",
+}
+
+// HarFilter selects the entries of a HAR archive to import. Empty fields match everything.
+type HarFilter struct {
+	Hosts        []string
+	UrlPattern   *regexp.Regexp
+	StripHeaders []string
+}
+
+// HarEntry is a recorded request converted into a request, with the status code it originally received.
+type HarEntry struct {
+	Request         *model.YamlRequest
+	Status          int
+	StartedDateTime time.Time
+}
+
+type harArchive struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime string `json:"startedDateTime"`
+	Request         struct {
+		Method   string         `json:"method"`
+		Url      string         `json:"url"`
+		Headers  []harNameValue `json:"headers"`
+		PostData *struct {
+			MimeType string         `json:"mimeType"`
+			Text     string         `json:"text"`
+			Params   []harNameValue `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status int `json:"status"`
+	} `json:"response"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ReadHar parses a HAR archive and returns the entries matching filter in the order they were started.
+func ReadHar(data []byte, filter HarFilter) ([]HarEntry, error) {
+	var archive harArchive
+	err := json.Unmarshal(data, &archive)
+	if err != nil {
+		return nil, err
+	}
+	if archive.Log.Entries == nil {
+		return nil, errors.New("not a HAR archive, log.entries is missing")
+	}
+
+	var entries []HarEntry
+	for _, e := range archive.Log.Entries {
+		u, err := url.Parse(e.Request.Url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid url %s: %w", e.Request.Url, err)
+		}
+		if len(filter.Hosts) > 0 && !slices.ContainsFunc(filter.Hosts, func(host string) bool {
+			return strings.EqualFold(host, u.Host) || strings.EqualFold(host, u.Hostname())
+		}) {
+			continue
+		}
+		if filter.UrlPattern != nil && !filter.UrlPattern.MatchString(e.Request.Url) {
+			continue
+		}
+
+		// unparseable timestamps sort first, the stable sort keeps their recorded order
+		started, _ := time.Parse(time.RFC3339Nano, e.StartedDateTime)
+		entries = append(entries, HarEntry{
+			Request:         harYamlRequest(e, filter),
+			Status:          e.Response.Status,
+			StartedDateTime: started,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return entries, nil
+}
+
+// ImportHar converts the entries of a HAR archive matching filter into request files under root.
+// Requests are numbered in the order they were recorded.
+func ImportHar(data []byte, root string, filter HarFilter) (Report, error) {
+	report := Report{}
+	entries, err := ReadHar(data, filter)
+	if err != nil {
+		return report, err
+	}
+
+	width := len(fmt.Sprint(len(entries)))
+	for i, entry := range entries {
+		entry.Request.Name = fmt.Sprintf("%0*d %s", width, i+1, SuggestName(entry.Request))
+		mold, err := SaveYamlRequest(root, entry.Request)
+		if err != nil {
+			report.warn("%s: %v", entry.Request.Name, err)
+			continue
+		}
+		report.Files = append(report.Files, filepath.Join(mold.Root, mold.Filename))
+	}
+	return report, nil
+}
+
+func harYamlRequest(e harEntry, filter HarFilter) *model.YamlRequest {
+	yamlRequest := &model.YamlRequest{
+		Url:     e.Request.Url,
+		Method:  strings.ToUpper(e.Request.Method),
+		Headers: make(model.Headers),
+	}
+
+	for _, h := range e.Request.Headers {
+		if isVolatileHarHeader(h.Name, filter.StripHeaders) {
+			continue
+		}
+		addHeader(yamlRequest.Headers, h.Name, h.Value)
+	}
+	// HTTP/2 sends every cookie in a header of its own
+	joinCookies(yamlRequest.Headers, nil)
+
+	if postData := e.Request.PostData; postData != nil {
+		switch {
+		case postData.Text != "":
+			yamlRequest.Body = postData.Text
+		case strings.HasPrefix(postData.MimeType, "multipart/form-data"):
+			var fields []formField
+			for _, p := range postData.Params {
+				fields = append(fields, formField{Name: p.Name, Value: p.Value})
+			}
+			body, contentType := multipartBody(fields)
+			setHeader(yamlRequest.Headers, "Content-Type", contentType)
+			yamlRequest.Body = body
+		case len(postData.Params) > 0:
+			values := url.Values{}
+			for _, p := range postData.Params {
+				values.Add(p.Name, p.Value)
+			}
+			yamlRequest.Body = values.Encode()
+		}
+		if postData.MimeType != "" && !hasHeader(yamlRequest.Headers, "Content-Type") {
+			yamlRequest.Headers["Content-Type"] = model.HeaderValues{postData.MimeType}
+		}
+	}
+
+	if len(yamlRequest.Headers) == 0 {
+		yamlRequest.Headers = nil
+	}
+	return yamlRequest
+}
+
+func isVolatileHarHeader(name string, strip []string) bool {
+	name = strings.ToLower(name)
+	// HTTP/2 pseudo headers and fetch metadata
+	if strings.HasPrefix(name, ":") || strings.HasPrefix(name, "sec-") {
+		return true
+	}
+	return slices.Contains(volatileHarHeaders, name) || slices.ContainsFunc(strip, func(s string) bool {
+		return strings.EqualFold(s, name)
+	})
+}
+
+func setHeader(headers model.Headers, name string, value string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+	headers[name] = model.HeaderValues{value}
+}
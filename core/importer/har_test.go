@@ -0,0 +1,105 @@
+package importer
+
+import (
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadHar(t *testing.T) {
+	data, err := os.ReadFile("testdata/session.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadHar(data, HarFilter{Hosts: []string{"api.example.com"}, StripHeaders: []string{"X-Trace-Id"}})
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedRequests := []*model.YamlRequest{
+		{
+			Url:    "https://api.example.com/v1/orders?page=1",
+			Method: "GET",
+			Headers: model.Headers{
+				"Accept": {"application/json"},
+			},
+		},
+		{
+			Url:    "https://api.example.com/v1/orders",
+			Method: "POST",
+			Headers: model.Headers{
+				"accept":       {"application/json"},
+				"cookie":       {"session=abc; theme=dark"},
+				"Content-Type": {"application/json"},
+			},
+			Body: `{"item":"a"}`,
+		},
+		{
+			Url:    "https://api.example.com/login",
+			Method: "POST",
+			Headers: model.Headers{
+				"Content-Type": {"application/x-www-form-urlencoded"},
+			},
+			Body: "pass=s3cret&user=jane",
+		},
+	}
+	wantedStatuses := []int{200, 201, 302}
+
+	if len(entries) != len(wantedRequests) {
+		t.Errorf("got %d, wanted %d", len(entries), len(wantedRequests))
+		return
+	}
+	for i, entry := range entries {
+		if !cmp.Equal(entry.Request, wantedRequests[i]) {
+			t.Errorf("got\n%v\nwanted\n%v", entry.Request, wantedRequests[i])
+		}
+		if entry.Status != wantedStatuses[i] {
+			t.Errorf("got %d, wanted %d", entry.Status, wantedStatuses[i])
+		}
+	}
+}
+
+func TestImportHarWithUrlPattern(t *testing.T) {
+	data, err := os.ReadFile("testdata/session.har")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	report, err := ImportHar(data, root, HarFilter{UrlPattern: regexp.MustCompile(`/v1/`)})
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedFiles := []string{
+		filepath.Join(root, "1 GET orders.yaml"),
+		filepath.Join(root, "2 POST orders.yaml"),
+	}
+	if !cmp.Equal(report.Files, wantedFiles) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Files, wantedFiles)
+	}
+
+	requests, err := loader.ReadRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if len(requests) != 2 {
+		t.Errorf("got %d, wanted %d", len(requests), 2)
+	}
+}
+
+func TestReadHarWithInvalidArchive(t *testing.T) {
+	_, err := ReadHar([]byte(`{"info": {}}`), HarFilter{})
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
@@ -0,0 +1,213 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"goful/core/client/validator"
+	"goful/core/model"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var (
+	httpFileVariablePattern = regexp.MustCompile(`^@([A-Za-z0-9_.-]+)\s*=\s*(.*)$`)
+	httpFileNamePattern     = regexp.MustCompile(`^(?:#|//)\s*@name\s+(.+)$`)
+	httpFileRequestPattern  = regexp.MustCompile(`^([A-Z]+)\s+(\S+)(?:\s+HTTP/[\d.]+)?$`)
+)
+
+type httpFileRequest struct {
+	title string
+	lines []string
+	line  int
+}
+
+// ImportHttpFile converts a VS Code REST Client or JetBrains .http file into request files under root.
+// @var declarations are written into the default profile.
+func ImportHttpFile(data []byte, source string, root string) (Report, error) {
+	report := Report{}
+	variables, requests := splitHttpFile(string(data))
+
+	keys := make([]string, 0, len(variables))
+	for k := range variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		key := EnvKey(k)
+		value := convertTemplate(variables[k], source, &report)
+		path, added, err := AddProfileVariable(root, "default", key, value)
+		if err != nil {
+			report.warn("failed to write variable %s to the default profile: %v", key, err)
+			continue
+		}
+		if !added {
+			report.warn("default profile already defines %s, it was left unchanged", key)
+			continue
+		}
+		if !slices.Contains(report.Files, path) {
+			report.Files = append(report.Files, path)
+		}
+	}
+
+	for _, r := range requests {
+		path := fmt.Sprintf("%s:%d", source, r.line)
+		yamlRequest, ok := parseHttpFileRequest(r, path, &report)
+		if !ok {
+			continue
+		}
+		mold, err := SaveYamlRequest(root, yamlRequest)
+		if err != nil {
+			report.warn("%s: %v", path, err)
+			continue
+		}
+		report.Files = append(report.Files, filepath.Join(mold.Root, mold.Filename))
+	}
+
+	return report, nil
+}
+
+// splitHttpFile separates variable declarations from ###-separated request blocks
+func splitHttpFile(content string) (map[string]string, []httpFileRequest) {
+	variables := make(map[string]string)
+	var requests []httpFileRequest
+	current := httpFileRequest{line: 1}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "###") {
+			requests = append(requests, current)
+			current = httpFileRequest{
+				title: strings.TrimSpace(strings.TrimLeft(line, "#")),
+				line:  lineNumber,
+			}
+			continue
+		}
+		if match := httpFileVariablePattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil && !hasRequestLine(current.lines) {
+			variables[match[1]] = strings.TrimSpace(match[2])
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	requests = append(requests, current)
+
+	var nonEmpty []httpFileRequest
+	for _, r := range requests {
+		if hasRequestLine(r.lines) {
+			nonEmpty = append(nonEmpty, r)
+		}
+	}
+	return variables, nonEmpty
+}
+
+func hasRequestLine(lines []string) bool {
+	for _, line := range lines {
+		if !isHttpFileComment(line) && strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isHttpFileComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//")
+}
+
+func parseHttpFileRequest(r httpFileRequest, path string, report *Report) (*model.YamlRequest, bool) {
+	yamlRequest := &model.YamlRequest{
+		Name:    r.title,
+		Headers: make(model.Headers),
+	}
+
+	const (
+		requestLine = iota
+		headers
+		body
+		responseHandler
+	)
+	state := requestLine
+	var bodyLines []string
+
+	for _, line := range r.lines {
+		switch state {
+		case requestLine:
+			if match := httpFileNamePattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				yamlRequest.Name = strings.TrimSpace(match[1])
+				continue
+			}
+			if isHttpFileComment(line) || strings.TrimSpace(line) == "" {
+				continue
+			}
+			trimmed := strings.TrimSpace(line)
+			if match := httpFileRequestPattern.FindStringSubmatch(trimmed); match != nil && validator.IsValidMethod(match[1]) {
+				yamlRequest.Method = match[1]
+				yamlRequest.Url = match[2]
+			} else if !strings.Contains(trimmed, " ") {
+				yamlRequest.Method = validator.DefaultBodilessMethod
+				yamlRequest.Url = trimmed
+			} else {
+				report.warn("%s: invalid request line %s", path, trimmed)
+				return nil, false
+			}
+			state = headers
+		case headers:
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				state = body
+				continue
+			}
+			if isHttpFileComment(line) {
+				continue
+			}
+			// multi-line query strings continue the url
+			if strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&") {
+				yamlRequest.Url += trimmed
+				continue
+			}
+			name, value, found := strings.Cut(trimmed, ":")
+			if !found {
+				report.warn("%s: invalid header %s", path, trimmed)
+				continue
+			}
+			addHeader(yamlRequest.Headers, strings.TrimSpace(name), convertTemplate(strings.TrimSpace(value), path, report))
+		case body:
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "<> ") || strings.HasPrefix(trimmed, "> ") {
+				report.warn("%s: response handlers and references are not supported and were skipped", path)
+				state = responseHandler
+				continue
+			}
+			if strings.HasPrefix(trimmed, "< ") {
+				report.warn("%s: reading body from file %s is not supported", path, strings.TrimSpace(trimmed[2:]))
+				continue
+			}
+			bodyLines = append(bodyLines, line)
+		}
+	}
+
+	if state == requestLine {
+		return nil, false
+	}
+
+	yamlRequest.Url = convertTemplate(yamlRequest.Url, path, report)
+	if rawBody := strings.TrimSpace(strings.Join(bodyLines, "\n")); rawBody != "" {
+		convertedBody := convertTemplate(rawBody, path, report)
+		if convertedBody != rawBody {
+			report.warn("%s: template variables in body are not substituted by goful", path)
+		}
+		yamlRequest.Body = convertedBody
+	}
+	if yamlRequest.Name == "" {
+		yamlRequest.Name = SuggestName(yamlRequest)
+	}
+	if len(yamlRequest.Headers) == 0 {
+		yamlRequest.Headers = nil
+	}
+	return yamlRequest, true
+}
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/joho/godotenv"
+)
+
+func TestImportHttpFile(t *testing.T) {
+	data, err := os.ReadFile("testdata/requests.http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	report, err := ImportHttpFile(data, "requests.http", root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedFiles := []string{
+		filepath.Join(root, ".env"),
+		filepath.Join(root, "List users.yaml"),
+		filepath.Join(root, "Create user.yaml"),
+		filepath.Join(root, "GET health.yaml"),
+	}
+	if !cmp.Equal(report.Files, wantedFiles) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Files, wantedFiles)
+	}
+
+	wantedWarnings := []string{
+		"requests.http:10: response handlers and references are not supported and were skipped",
+	}
+	if !cmp.Equal(report.Warnings, wantedWarnings) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Warnings, wantedWarnings)
+	}
+
+	env, err := godotenv.Read(filepath.Join(root, ".env"))
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wantedEnv := map[string]string{"base_url": "https://api.example.com", "token": "abc123"}
+	if !cmp.Equal(env, wantedEnv) {
+		t.Errorf("got\n%v\nwanted\n%v", env, wantedEnv)
+	}
+
+	requests, err := loader.ReadRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedRequests := map[string]*model.YamlRequest{
+		"List users": {
+			Name:   "List users",
+			Url:    "{base_url}/users?page=1&size=20",
+			Method: "GET",
+			Headers: model.Headers{
+				"Accept": {"application/json"},
+			},
+		},
+		"Create user": {
+			Name:   "Create user",
+			Url:    "{base_url}/users",
+			Method: "POST",
+			Headers: model.Headers{
+				"Authorization": {"Bearer {token}"},
+				"Content-Type":  {"application/json"},
+			},
+			Body: "{\n  \"name\": \"Jane\"\n}",
+		},
+		"GET health": {
+			Name:   "GET health",
+			Url:    "https://api.example.com/health",
+			Method: "GET",
+		},
+	}
+	if len(requests) != len(wantedRequests) {
+		t.Errorf("got %d, wanted %d", len(requests), len(wantedRequests))
+		return
+	}
+	for _, r := range requests {
+		wanted, ok := wantedRequests[r.Name()]
+		if !ok {
+			t.Errorf("did not expect request %s", r.Name())
+			continue
+		}
+		got := r.Yaml
+		got.Raw = ""
+		if !cmp.Equal(got, wanted) {
+			t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const baseUrlVariable = "base_url"
+
+// openapiExampleDepth limits how deep nested schemas are expanded into example bodies
+const openapiExampleDepth = 8
+
+type openapiSpec struct {
+	Openapi    string                     `yaml:"openapi"`
+	Servers    []openapiServer            `yaml:"servers"`
+	Paths      map[string]openapiPathItem `yaml:"paths"`
+	Components openapiComponents          `yaml:"components"`
+}
+
+type openapiServer struct {
+	Url       string `yaml:"url"`
+	Variables map[string]struct {
+		Default string `yaml:"default"`
+	} `yaml:"variables"`
+}
+
+type openapiComponents struct {
+	Schemas       map[string]*openapiSchema     `yaml:"schemas"`
+	Parameters    map[string]openapiParameter   `yaml:"parameters"`
+	RequestBodies map[string]openapiRequestBody `yaml:"requestBodies"`
+}
+
+type openapiPathItem struct {
+	Parameters []openapiParameter `yaml:"parameters"`
+	Get        *openapiOperation  `yaml:"get"`
+	Put        *openapiOperation  `yaml:"put"`
+	Post       *openapiOperation  `yaml:"post"`
+	Delete     *openapiOperation  `yaml:"delete"`
+	Options    *openapiOperation  `yaml:"options"`
+	Head       *openapiOperation  `yaml:"head"`
+	Patch      *openapiOperation  `yaml:"patch"`
+	Trace      *openapiOperation  `yaml:"trace"`
+}
+
+type openapiMethodOperation struct {
+	method    string
+	operation *openapiOperation
+}
+
+func (p openapiPathItem) operations() []openapiMethodOperation {
+	all := []openapiMethodOperation{
+		{http.MethodGet, p.Get},
+		{http.MethodPut, p.Put},
+		{http.MethodPost, p.Post},
+		{http.MethodDelete, p.Delete},
+		{http.MethodOptions, p.Options},
+		{http.MethodHead, p.Head},
+		{http.MethodPatch, p.Patch},
+		{http.MethodTrace, p.Trace},
+	}
+	var defined []openapiMethodOperation
+	for _, o := range all {
+		if o.operation != nil {
+			defined = append(defined, o)
+		}
+	}
+	return defined
+}
+
+type openapiOperation struct {
+	OperationId string              `yaml:"operationId"`
+	Summary     string              `yaml:"summary"`
+	Tags        []string            `yaml:"tags"`
+	Parameters  []openapiParameter  `yaml:"parameters"`
+	RequestBody *openapiRequestBody `yaml:"requestBody"`
+}
+
+type openapiParameter struct {
+	Ref      string         `yaml:"$ref"`
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Example  interface{}    `yaml:"example"`
+	Schema   *openapiSchema `yaml:"schema"`
+}
+
+type openapiRequestBody struct {
+	Ref     string                      `yaml:"$ref"`
+	Content map[string]openapiMediaType `yaml:"content"`
+}
+
+type openapiMediaType struct {
+	Schema   *openapiSchema `yaml:"schema"`
+	Example  interface{}    `yaml:"example"`
+	Examples map[string]struct {
+		Value interface{} `yaml:"value"`
+	} `yaml:"examples"`
+}
+
+type openapiSchema struct {
+	Ref        string                    `yaml:"$ref"`
+	Type       interface{}               `yaml:"type"`
+	Format     string                    `yaml:"format"`
+	Properties map[string]*openapiSchema `yaml:"properties"`
+	Items      *openapiSchema            `yaml:"items"`
+	Example    interface{}               `yaml:"example"`
+	Default    interface{}               `yaml:"default"`
+	Enum       []interface{}             `yaml:"enum"`
+	AllOf      []*openapiSchema          `yaml:"allOf"`
+	OneOf      []*openapiSchema          `yaml:"oneOf"`
+	AnyOf      []*openapiSchema          `yaml:"anyOf"`
+}
+
+// schemaType returns the type of the schema, taking the first non-null one of OpenAPI 3.1 type lists
+func (s *openapiSchema) schemaType() string {
+	switch t := s.Type.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if str, ok := v.(string); ok && str != "null" {
+				return str
+			}
+		}
+	}
+	if len(s.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+// ImportOpenApi generates one request file per operation of an OpenAPI 3 specification under root, grouped into one
+// sub directory per first tag of the operations. The first server url is written into the default profile as base_url,
+// the parameters of the operations are written into it as variables of the same names.
+func ImportOpenApi(data []byte, root string) (Report, error) {
+	report := Report{}
+	var spec openapiSpec
+	err := yaml.Unmarshal(data, &spec)
+	if err != nil {
+		return report, fmt.Errorf("failed to parse OpenAPI specification: %w", err)
+	}
+	if !strings.HasPrefix(spec.Openapi, "3.") {
+		return report, errors.New("file is not an OpenAPI 3 specification")
+	}
+
+	importOpenApiServers(spec, root, &report)
+
+	variables := make(map[string]string)
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		pathItem := spec.Paths[p]
+		for _, o := range pathItem.operations() {
+			operation := o.operation
+			name := operation.OperationId
+			if name == "" {
+				name = operation.Summary
+			}
+			if name == "" {
+				name = fmt.Sprintf("%s %s", o.method, p)
+			}
+
+			yamlRequest := convertOpenApiOperation(spec, name, o.method, p, pathItem.Parameters, operation, variables, &report)
+
+			dir := ""
+			if len(operation.Tags) > 0 {
+				dir = sanitizeFilename(operation.Tags[0])
+			}
+			mold, err := saveYamlRequest(root, dir, yamlRequest)
+			if err != nil {
+				report.warn("%s %s: %v", o.method, p, err)
+				continue
+			}
+			report.Files = append(report.Files, filepath.Join(mold.Root, mold.Filename))
+		}
+	}
+
+	importOpenApiParameters(variables, root, &report)
+	return report, nil
+}
+
+// importOpenApiParameters writes the variables the parameters of the requests refer to into the default profile
+func importOpenApiParameters(variables map[string]string, root string, report *Report) {
+	keys := make([]string, 0, len(variables))
+	for key := range variables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		path, added, err := AddProfileVariable(root, "default", key, variables[key])
+		if err != nil {
+			report.warn("failed to write %s to the default profile: %v", key, err)
+			return
+		}
+		if added && !slices.Contains(report.Files, path) {
+			report.Files = append(report.Files, path)
+		}
+	}
+}
+
+func importOpenApiServers(spec openapiSpec, root string, report *Report) {
+	if len(spec.Servers) == 0 {
+		report.warn("specification has no servers, define %s in a profile", baseUrlVariable)
+		return
+	}
+
+	server := spec.Servers[0]
+	serverUrl := server.Url
+	for name, variable := range server.Variables {
+		serverUrl = strings.ReplaceAll(serverUrl, "{"+name+"}", variable.Default)
+	}
+	serverUrl = strings.TrimSuffix(serverUrl, "/")
+	if !strings.Contains(serverUrl, "://") {
+		report.warn("server url %s is relative, prefix it with the scheme and host in your profile", serverUrl)
+	}
+	for _, other := range spec.Servers[1:] {
+		report.warn("server %s was not imported, only the first server is used as %s", other.Url, baseUrlVariable)
+	}
+
+	path, added, err := AddProfileVariable(root, "default", baseUrlVariable, serverUrl)
+	if err != nil {
+		report.warn("failed to write %s to the default profile: %v", baseUrlVariable, err)
+		return
+	}
+	if !added {
+		report.warn("default profile already defines %s, it was left unchanged", baseUrlVariable)
+		return
+	}
+	report.Files = append(report.Files, path)
+}
+
+// convertOpenApiOperation adds the variables the parameters of the request refer to, with their example values, to
+// variables. Optional query and header parameters without an example or default are left out.
+func convertOpenApiOperation(spec openapiSpec, name string, method string, path string, pathParameters []openapiParameter, operation *openapiOperation, variables map[string]string, report *Report) *model.YamlRequest {
+	yamlRequest := &model.YamlRequest{
+		Name:    name,
+		Method:  method,
+		Headers: make(model.Headers),
+	}
+
+	// operation level parameters override path level ones with the same name and location
+	parameters := make(map[string]openapiParameter)
+	var order []string
+	for _, parameter := range append(append([]openapiParameter{}, pathParameters...), operation.Parameters...) {
+		resolved, ok := resolveOpenApiParameter(spec, parameter)
+		if !ok {
+			report.warn("%s %s: could not resolve parameter %s", method, path, parameter.Ref)
+			continue
+		}
+		key := resolved.In + ":" + resolved.Name
+		if _, exists := parameters[key]; !exists {
+			order = append(order, key)
+		}
+		parameters[key] = resolved
+	}
+
+	urlPath := path
+	var query []string
+	for _, key := range order {
+		parameter := parameters[key]
+		if !slices.Contains([]string{"path", "query", "header"}, parameter.In) {
+			report.warn("%s %s: %s parameter %s is not supported", method, path, parameter.In, parameter.Name)
+			continue
+		}
+		value, hasValue := openApiParameterValue(spec, parameter)
+		if parameter.In != "path" && !parameter.Required && !hasValue {
+			continue
+		}
+		variable := EnvKey(parameter.Name)
+		switch parameter.In {
+		case "path":
+			// path parameters already use the {name} template syntax, only the name may need converting
+			urlPath = strings.ReplaceAll(urlPath, "{"+parameter.Name+"}", "{"+variable+"}")
+		case "query":
+			query = append(query, fmt.Sprintf("%s={%s}", url.QueryEscape(parameter.Name), variable))
+		case "header":
+			addHeader(yamlRequest.Headers, parameter.Name, fmt.Sprintf("{%s}", variable))
+		}
+		if _, exists := variables[variable]; !exists || hasValue {
+			variables[variable] = value
+		}
+	}
+
+	yamlRequest.Url = fmt.Sprintf("{%s}%s", baseUrlVariable, urlPath)
+	if len(query) > 0 {
+		yamlRequest.Url += "?" + strings.Join(query, "&")
+	}
+
+	if operation.RequestBody != nil {
+		convertOpenApiRequestBody(spec, yamlRequest, *operation.RequestBody, method, path, report)
+	}
+
+	if len(yamlRequest.Headers) == 0 {
+		yamlRequest.Headers = nil
+	}
+	return yamlRequest
+}
+
+// openApiParameterValue returns the example or default value of a parameter
+func openApiParameterValue(spec openapiSpec, parameter openapiParameter) (string, bool) {
+	if parameter.Example != nil {
+		return fmt.Sprint(parameter.Example), true
+	}
+	schema := parameter.Schema
+	if schema != nil && schema.Ref != "" {
+		schema = spec.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	if schema == nil {
+		return "", false
+	}
+	if schema.Example != nil {
+		return fmt.Sprint(schema.Example), true
+	}
+	if schema.Default != nil {
+		return fmt.Sprint(schema.Default), true
+	}
+	return "", false
+}
+
+func resolveOpenApiParameter(spec openapiSpec, parameter openapiParameter) (openapiParameter, bool) {
+	if parameter.Ref == "" {
+		return parameter, true
+	}
+	resolved, ok := spec.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+	return resolved, ok
+}
+
+func convertOpenApiRequestBody(spec openapiSpec, yamlRequest *model.YamlRequest, requestBody openapiRequestBody, method string, path string, report *Report) {
+	if requestBody.Ref != "" {
+		resolved, ok := spec.Components.RequestBodies[strings.TrimPrefix(requestBody.Ref, "#/components/requestBodies/")]
+		if !ok {
+			report.warn("%s %s: could not resolve request body %s", method, path, requestBody.Ref)
+			return
+		}
+		requestBody = resolved
+	}
+
+	contentTypes := make([]string, 0, len(requestBody.Content))
+	for contentType := range requestBody.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	if len(contentTypes) == 0 {
+		return
+	}
+	sort.Strings(contentTypes)
+	contentType := contentTypes[0]
+	for _, c := range contentTypes {
+		if strings.HasPrefix(c, "application/json") {
+			contentType = c
+			break
+		}
+	}
+	mediaType := requestBody.Content[contentType]
+
+	example := mediaType.Example
+	if example == nil {
+		names := make([]string, 0, len(mediaType.Examples))
+		for exampleName := range mediaType.Examples {
+			names = append(names, exampleName)
+		}
+		sort.Strings(names)
+		if len(names) > 0 {
+			example = mediaType.Examples[names[0]].Value
+		}
+	}
+	if example == nil && mediaType.Schema != nil {
+		example = openApiSchemaExample(spec, mediaType.Schema, 0, map[string]bool{})
+	}
+
+	addHeader(yamlRequest.Headers, "Content-Type", contentType)
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		b, err := json.MarshalIndent(example, "", "  ")
+		if err != nil {
+			report.warn("%s %s: failed to render example body: %v", method, path, err)
+			return
+		}
+		yamlRequest.Body = string(b)
+	case contentType == "application/x-www-form-urlencoded":
+		object, ok := example.(map[string]interface{})
+		if !ok {
+			report.warn("%s %s: example body is not an object", method, path)
+			return
+		}
+		values := url.Values{}
+		for k, v := range object {
+			values.Set(k, fmt.Sprintf("%v", v))
+		}
+		yamlRequest.Body = values.Encode()
+	default:
+		if s, ok := example.(string); ok {
+			yamlRequest.Body = s
+		} else {
+			report.warn("%s %s: example body for %s can not be generated", method, path, contentType)
+		}
+	}
+}
+
+func openApiSchemaExample(spec openapiSpec, schema *openapiSchema, depth int, visiting map[string]bool) interface{} {
+	if schema == nil || depth > openapiExampleDepth {
+		return nil
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if visiting[name] {
+			return nil
+		}
+		resolved, ok := spec.Components.Schemas[name]
+		if !ok {
+			return nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		return openApiSchemaExample(spec, resolved, depth+1, visiting)
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, s := range schema.AllOf {
+			if object, ok := openApiSchemaExample(spec, s, depth+1, visiting).(map[string]interface{}); ok {
+				for k, v := range object {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return openApiSchemaExample(spec, schema.OneOf[0], depth+1, visiting)
+	}
+	if len(schema.AnyOf) > 0 {
+		return openApiSchemaExample(spec, schema.AnyOf[0], depth+1, visiting)
+	}
+
+	switch schema.schemaType() {
+	case "object":
+		object := make(map[string]interface{})
+		for name, property := range schema.Properties {
+			object[name] = openApiSchemaExample(spec, property, depth+1, visiting)
+		}
+		return object
+	case "array":
+		item := openApiSchemaExample(spec, schema.Items, depth+1, visiting)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return false
+	case "string":
+		switch schema.Format {
+		case "date":
+			return "2024-01-01"
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "email":
+			return "user@example.com"
+		}
+		return "string"
+	}
+	return nil
+}
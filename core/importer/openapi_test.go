@@ -0,0 +1,144 @@
+package importer
+
+import (
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImportOpenApi(t *testing.T) {
+	data, err := os.ReadFile("testdata/openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	report, err := ImportOpenApi(data, root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedFiles := []string{
+		filepath.Join(root, ".env"),
+		filepath.Join(root, "GET _health.yaml"),
+		filepath.Join(root, "users", "listUsers.yaml"),
+		filepath.Join(root, "users", "createUser.yaml"),
+		filepath.Join(root, "admin", "Delete user.yaml"),
+	}
+	if !cmp.Equal(report.Files, wantedFiles) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Files, wantedFiles)
+	}
+
+	wantedWarnings := []string{
+		"server https://example.com/api was not imported, only the first server is used as base_url",
+		"GET /health: cookie parameter session is not supported",
+	}
+	if !cmp.Equal(report.Warnings, wantedWarnings) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Warnings, wantedWarnings)
+	}
+
+	profiles, err := loader.ReadProfiles(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	// every template variable of the requests is defined, offset is optional and has no example so it is left out
+	wantedVariables := map[string]string{
+		"base_url":     "https://staging.example.com/api",
+		"X_Request_Id": "",
+		"id":           "",
+		"limit":        "20",
+	}
+	if len(profiles) != 1 || !cmp.Equal(profiles[0].Variables, wantedVariables) {
+		t.Errorf("got\n%v\nwanted\n%v", profiles, wantedVariables)
+	}
+
+	requests, err := loader.ReadRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedRequests := map[string]*model.YamlRequest{
+		"listUsers": {
+			Name:   "listUsers",
+			Url:    "{base_url}/users?limit={limit}",
+			Method: "GET",
+			Headers: model.Headers{
+				"X-Request-Id": {"{X_Request_Id}"},
+			},
+		},
+		"createUser": {
+			Name:   "createUser",
+			Url:    "{base_url}/users",
+			Method: "POST",
+			Headers: model.Headers{
+				"Content-Type": {"application/json"},
+			},
+			Body: `{
+  "email": "user@example.com",
+  "manager": null,
+  "name": "Jane",
+  "roles": [
+    "admin"
+  ]
+}`,
+		},
+	}
+
+	if len(requests) != 4 {
+		t.Errorf("got %d, wanted %d", len(requests), 4)
+		return
+	}
+	// the tag directories are read back with the requests
+	wantedFilenames := map[string]string{
+		"GET /health": "GET _health.yaml",
+		"listUsers":   filepath.Join("users", "listUsers.yaml"),
+		"createUser":  filepath.Join("users", "createUser.yaml"),
+		"Delete user": filepath.Join("admin", "Delete user.yaml"),
+	}
+	for _, request := range requests {
+		if wanted := wantedFilenames[request.Name()]; request.Filename != wanted {
+			t.Errorf("got %s, wanted %s", request.Filename, wanted)
+		}
+	}
+	for _, request := range requests {
+		got := request.Yaml
+		got.Raw = ""
+		wanted, ok := wantedRequests[got.Name]
+		if ok && !cmp.Equal(got, wanted) {
+			t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+		}
+	}
+}
+
+func TestImportOpenApiKeepsExistingBaseUrl(t *testing.T) {
+	root := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, ".env"), []byte("base_url=http://localhost:8080"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ImportOpenApi([]byte("openapi: 3.1.0\nservers:\n  - url: https://example.com\npaths: {}\n"), root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedWarnings := []string{"default profile already defines base_url, it was left unchanged"}
+	if !cmp.Equal(report.Warnings, wantedWarnings) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Warnings, wantedWarnings)
+	}
+}
+
+func TestImportOpenApiRejectsSwagger(t *testing.T) {
+	_, err := ImportOpenApi([]byte("swagger: '2.0'\n"), t.TempDir())
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
@@ -0,0 +1,401 @@
+package importer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth"`
+	Event    []json.RawMessage `json:"event"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name string `json:"name"`
+}
+
+type postmanItem struct {
+	Name    string            `json:"name"`
+	Item    []postmanItem     `json:"item"`
+	Request *postmanRequest   `json:"request"`
+	Auth    *postmanAuth      `json:"auth"`
+	Event   []json.RawMessage `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	Body   *postmanBody      `json:"body"`
+	Url    postmanUrl        `json:"url"`
+	Auth   *postmanAuth      `json:"auth"`
+}
+
+// a request may be given as a plain url string
+func (r *postmanRequest) UnmarshalJSON(data []byte) error {
+	var rawUrl string
+	if err := json.Unmarshal(data, &rawUrl); err == nil {
+		r.Method = "GET"
+		r.Url = postmanUrl{Raw: rawUrl}
+		return nil
+	}
+	type request postmanRequest
+	return json.Unmarshal(data, (*request)(r))
+}
+
+type postmanUrl struct {
+	Raw   string            `json:"raw"`
+	Host  []string          `json:"host"`
+	Path  []string          `json:"path"`
+	Query []postmanKeyValue `json:"query"`
+}
+
+// an url may be given as a plain string
+func (u *postmanUrl) UnmarshalJSON(data []byte) error {
+	var rawUrl string
+	if err := json.Unmarshal(data, &rawUrl); err == nil {
+		u.Raw = rawUrl
+		return nil
+	}
+	type postmanUrlObject postmanUrl
+	return json.Unmarshal(data, (*postmanUrlObject)(u))
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	Urlencoded []postmanKeyValue `json:"urlencoded"`
+	Formdata   []postmanKeyValue `json:"formdata"`
+	Graphql    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options *struct {
+		Raw *struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer"`
+	Basic  []postmanKeyValue `json:"basic"`
+	Apikey []postmanKeyValue `json:"apikey"`
+}
+
+type postmanKeyValue struct {
+	Key      string      `json:"key"`
+	Value    interface{} `json:"value"`
+	Type     string      `json:"type"`
+	Disabled bool        `json:"disabled"`
+	Enabled  *bool       `json:"enabled"`
+}
+
+func (kv postmanKeyValue) value() string {
+	switch v := kv.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+func (kv postmanKeyValue) active() bool {
+	return !kv.Disabled && (kv.Enabled == nil || *kv.Enabled)
+}
+
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanKeyValue `json:"values"`
+}
+
+// IsPostmanCollection tells apart collections from environments, which are both plain JSON
+func IsPostmanCollection(data []byte) bool {
+	var probe struct {
+		Info *postmanInfo  `json:"info"`
+		Item []postmanItem `json:"item"`
+	}
+	err := json.Unmarshal(data, &probe)
+	return err == nil && probe.Info != nil
+}
+
+// ImportPostmanCollection converts a Postman v2.1 collection into request files under root,
+// one sub directory per Postman folder.
+func ImportPostmanCollection(data []byte, root string) (Report, error) {
+	report := Report{}
+	var collection postmanCollection
+	err := json.Unmarshal(data, &collection)
+	if err != nil {
+		return report, fmt.Errorf("failed to parse Postman collection: %w", err)
+	}
+	if collection.Info.Name == "" && len(collection.Item) == 0 {
+		return report, errors.New("file is not a Postman collection")
+	}
+
+	if len(collection.Event) > 0 {
+		report.warn("collection %s: scripts are not supported and were skipped", collection.Info.Name)
+	}
+	for _, v := range collection.Variable {
+		report.warn("collection %s: collection variable %s was not imported, define it in a profile", collection.Info.Name, v.Key)
+	}
+
+	importPostmanItems(collection.Item, root, "", collection.Auth, collection.Info.Name, &report)
+	return report, nil
+}
+
+// importPostmanItems saves the requests of items into dir under root, folders become sub directories of dir
+func importPostmanItems(items []postmanItem, root string, dir string, auth *postmanAuth, path string, report *Report) {
+	for _, item := range items {
+		itemPath := path + "/" + item.Name
+		if len(item.Event) > 0 {
+			report.warn("%s: scripts are not supported and were skipped", itemPath)
+		}
+
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+
+		if item.Request == nil {
+			importPostmanItems(item.Item, root, filepath.Join(dir, sanitizeFilename(item.Name)), itemAuth, itemPath, report)
+			continue
+		}
+
+		if item.Request.Auth != nil {
+			itemAuth = item.Request.Auth
+		}
+		yamlRequest := convertPostmanRequest(item.Name, item.Request, itemAuth, itemPath, report)
+		mold, err := saveYamlRequest(root, dir, yamlRequest)
+		if err != nil {
+			report.warn("%s: %v", itemPath, err)
+			continue
+		}
+		report.Files = append(report.Files, filepath.Join(mold.Root, mold.Filename))
+	}
+}
+
+func convertPostmanRequest(name string, request *postmanRequest, auth *postmanAuth, path string, report *Report) *model.YamlRequest {
+	yamlRequest := &model.YamlRequest{
+		Name:    name,
+		Method:  strings.ToUpper(request.Method),
+		Url:     convertTemplate(postmanRawUrl(request.Url), path, report),
+		Headers: make(model.Headers),
+	}
+	if yamlRequest.Method == "" {
+		yamlRequest.Method = "GET"
+	}
+
+	for _, header := range request.Header {
+		if !header.active() {
+			continue
+		}
+		addHeader(yamlRequest.Headers, header.Key, convertTemplate(header.value(), path, report))
+	}
+
+	convertPostmanAuth(yamlRequest, auth, path, report)
+
+	if request.Body != nil {
+		convertPostmanBody(yamlRequest, request.Body, path, report)
+	}
+
+	if len(yamlRequest.Headers) == 0 {
+		yamlRequest.Headers = nil
+	}
+	return yamlRequest
+}
+
+func postmanRawUrl(u postmanUrl) string {
+	if u.Raw != "" {
+		return u.Raw
+	}
+	rawUrl := strings.Join(u.Host, ".")
+	if len(u.Path) > 0 {
+		rawUrl += "/" + strings.Join(u.Path, "/")
+	}
+	var query []string
+	for _, q := range u.Query {
+		if q.active() {
+			query = append(query, q.Key+"="+q.value())
+		}
+	}
+	if len(query) > 0 {
+		rawUrl += "?" + strings.Join(query, "&")
+	}
+	return rawUrl
+}
+
+func convertPostmanAuth(yamlRequest *model.YamlRequest, auth *postmanAuth, path string, report *Report) {
+	if auth == nil {
+		return
+	}
+	switch auth.Type {
+	case "noauth", "":
+	case "bearer":
+		token := convertTemplate(postmanAuthValue(auth.Bearer, "token"), path, report)
+		addHeader(yamlRequest.Headers, "Authorization", "Bearer "+token)
+	case "basic":
+		username := postmanAuthValue(auth.Basic, "username")
+		password := postmanAuthValue(auth.Basic, "password")
+		if doubleBraceVariablePattern.MatchString(username + password) {
+			report.warn("%s: basic auth with template variables can not be encoded, set the Authorization header manually", path)
+			return
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+		addHeader(yamlRequest.Headers, "Authorization", "Basic "+credentials)
+	case "apikey":
+		key := convertTemplate(postmanAuthValue(auth.Apikey, "key"), path, report)
+		value := convertTemplate(postmanAuthValue(auth.Apikey, "value"), path, report)
+		if postmanAuthValue(auth.Apikey, "in") == "query" {
+			separator := "?"
+			if strings.Contains(yamlRequest.Url, "?") {
+				separator = "&"
+			}
+			yamlRequest.Url += separator + key + "=" + url.QueryEscape(value)
+		} else {
+			addHeader(yamlRequest.Headers, key, value)
+		}
+	default:
+		report.warn("%s: auth type %s is not supported", path, auth.Type)
+	}
+}
+
+func postmanAuthValue(values []postmanKeyValue, key string) string {
+	for _, v := range values {
+		if v.Key == key {
+			return v.value()
+		}
+	}
+	return ""
+}
+
+func convertPostmanBody(yamlRequest *model.YamlRequest, body *postmanBody, path string, report *Report) {
+	hasVariables := false
+	convert := func(s string) string {
+		converted := convertTemplate(s, path, report)
+		if converted != s {
+			hasVariables = true
+		}
+		return converted
+	}
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return
+		}
+		yamlRequest.Body = convert(body.Raw)
+		if body.Options != nil && body.Options.Raw != nil && !hasHeader(yamlRequest.Headers, "Content-Type") {
+			switch body.Options.Raw.Language {
+			case "json":
+				addHeader(yamlRequest.Headers, "Content-Type", "application/json")
+			case "xml":
+				addHeader(yamlRequest.Headers, "Content-Type", "application/xml")
+			}
+		}
+	case "urlencoded":
+		var values []string
+		for _, kv := range body.Urlencoded {
+			if kv.active() {
+				values = append(values, url.QueryEscape(kv.Key)+"="+url.QueryEscape(kv.value()))
+			}
+		}
+		yamlRequest.Body = convert(strings.Join(values, "&"))
+		if !hasHeader(yamlRequest.Headers, "Content-Type") {
+			addHeader(yamlRequest.Headers, "Content-Type", "application/x-www-form-urlencoded")
+		}
+	case "formdata":
+		var fields []formField
+		for _, kv := range body.Formdata {
+			if !kv.active() {
+				continue
+			}
+			if kv.Type == "file" {
+				report.warn("%s: file field %s in form data is not supported and was skipped", path, kv.Key)
+				continue
+			}
+			fields = append(fields, formField{kv.Key, convert(kv.value())})
+		}
+		body, contentType := multipartBody(fields)
+		yamlRequest.Body = body
+		yamlRequest.Headers["Content-Type"] = model.HeaderValues{contentType}
+	case "graphql":
+		if body.Graphql == nil {
+			return
+		}
+		graphql := map[string]interface{}{"query": body.Graphql.Query}
+		if body.Graphql.Variables != "" {
+			graphql["variables"] = json.RawMessage(body.Graphql.Variables)
+		}
+		b, err := json.Marshal(graphql)
+		if err != nil {
+			report.warn("%s: graphql variables are not valid JSON: %v", path, err)
+			return
+		}
+		yamlRequest.Body = convert(string(b))
+		if !hasHeader(yamlRequest.Headers, "Content-Type") {
+			addHeader(yamlRequest.Headers, "Content-Type", "application/json")
+		}
+	case "":
+	default:
+		report.warn("%s: body mode %s is not supported", path, body.Mode)
+	}
+
+	if hasVariables {
+		report.warn("%s: template variables in body are not substituted by goful", path)
+	}
+}
+
+// ImportPostmanEnvironment converts a Postman environment into a .env.<name> profile file under root
+func ImportPostmanEnvironment(data []byte, root string) (Report, error) {
+	report := Report{}
+	var environment postmanEnvironment
+	err := json.Unmarshal(data, &environment)
+	if err != nil {
+		return report, fmt.Errorf("failed to parse Postman environment: %w", err)
+	}
+	if environment.Name == "" {
+		return report, errors.New("file is not a Postman environment")
+	}
+
+	variables := make(map[string]string)
+	for _, v := range environment.Values {
+		if !v.active() {
+			report.warn("environment %s: disabled variable %s was skipped", environment.Name, v.Key)
+			continue
+		}
+		key := EnvKey(v.Key)
+		if key != v.Key {
+			report.warn("environment %s: variable %s was renamed to %s", environment.Name, v.Key, key)
+		}
+		variables[key] = v.value()
+	}
+
+	path, err := SaveProfile(root, ProfileName(environment.Name), variables)
+	if err != nil {
+		return report, err
+	}
+	report.Files = append(report.Files, path)
+	return report, nil
+}
+
+// ProfileName converts a name to one usable in .env.<name>, which must not contain dots
+func ProfileName(name string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '.' || r == ' ' || r == '/' || r == '\\' {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name)))
+}
@@ -0,0 +1,154 @@
+package importer
+
+import (
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImportPostmanCollection(t *testing.T) {
+	data, err := os.ReadFile("testdata/postman_collection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	if !IsPostmanCollection(data) {
+		t.Errorf("expected file to be detected as a collection")
+	}
+
+	report, err := ImportPostmanCollection(data, root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedFiles := []string{
+		filepath.Join(root, "List users.yaml"),
+		filepath.Join(root, "Admin", "Create user.yaml"),
+	}
+	if !cmp.Equal(report.Files, wantedFiles) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Files, wantedFiles)
+	}
+
+	wantedWarnings := []string{
+		"Users API/Admin/Create user: scripts are not supported and were skipped",
+		"Users API/Admin/Create user: dynamic variable $randomFirstName is not supported",
+	}
+	if !cmp.Equal(report.Warnings, wantedWarnings) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Warnings, wantedWarnings)
+	}
+
+	requests, err := loader.ReadRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if len(requests) != 2 {
+		t.Errorf("got %d, wanted %d", len(requests), 2)
+		return
+	}
+
+	wantedRequest := &model.YamlRequest{
+		Name:   "List users",
+		Url:    "{base_url}/users?page=1",
+		Method: "GET",
+		Headers: model.Headers{
+			"Accept":        {"application/json"},
+			"Authorization": {"Bearer {api_token}"},
+		},
+	}
+	got := requests[1].Yaml
+	got.Raw = ""
+	if !cmp.Equal(got, wantedRequest) {
+		t.Errorf("got\n%v\nwanted\n%v", got, wantedRequest)
+	}
+
+	wantedBody := `{"name": "{{$randomFirstName}}"}`
+	// folders are read back as sub directories of the workspace
+	if requests[0].Filename != filepath.Join("Admin", "Create user.yaml") || requests[0].Root != root {
+		t.Errorf("got %s in %s, wanted Admin/Create user.yaml in %s", requests[0].Filename, requests[0].Root, root)
+	}
+	if requests[0].Yaml.Name != "Create user" || requests[0].Yaml.Body != wantedBody {
+		t.Errorf("got\n%v\nwanted body\n%v", requests, wantedBody)
+	}
+}
+
+func TestImportPostmanEnvironment(t *testing.T) {
+	data, err := os.ReadFile("testdata/postman_environment.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+
+	if IsPostmanCollection(data) {
+		t.Errorf("did not expect file to be detected as a collection")
+	}
+
+	report, err := ImportPostmanEnvironment(data, root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedWarnings := []string{
+		"environment Staging: variable api-token was renamed to api_token",
+		"environment Staging: disabled variable unused was skipped",
+	}
+	if !cmp.Equal(report.Warnings, wantedWarnings) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Warnings, wantedWarnings)
+	}
+
+	profiles, err := loader.ReadProfiles(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+
+	wantedProfiles := []model.Profile{
+		{
+			Name: "staging",
+			Variables: map[string]string{
+				"base_url":  "https://staging.example.com",
+				"api_token": "0123",
+			},
+		},
+	}
+	if !cmp.Equal(profiles, wantedProfiles) {
+		t.Errorf("got\n%v\nwanted\n%v", profiles, wantedProfiles)
+	}
+}
+
+func TestImportPostmanCollectionStaysInWorkspace(t *testing.T) {
+	data := []byte(`{
+  "info": {"name": "Escape"},
+  "item": [
+    {"name": "..", "item": [
+      {"name": "..", "request": {"method": "GET", "url": "https://example.com"}}
+    ]}
+  ]
+}`)
+	parent := t.TempDir()
+	root := filepath.Join(parent, "workspace")
+
+	report, err := ImportPostmanCollection(data, root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wantedFiles := []string{filepath.Join(root, "__", "__.yaml")}
+	if !cmp.Equal(report.Files, wantedFiles) {
+		t.Errorf("got\n%v\nwanted\n%v", report.Files, wantedFiles)
+	}
+	entries, err := os.ReadDir(parent)
+	if err != nil || len(entries) != 1 {
+		t.Errorf("expected only the workspace in %s, got %v and error %v", parent, entries, err)
+	}
+	if sanitizeFilename("..") != "__" || sanitizeFilename(".") != "_" || sanitizeFilename(".git") != "_git" {
+		t.Errorf("expected ., .. and hidden names to be replaced, got %s, %s and %s", sanitizeFilename(".."), sanitizeFilename("."), sanitizeFilename(".git"))
+	}
+}
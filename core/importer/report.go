@@ -0,0 +1,13 @@
+package importer
+
+import "fmt"
+
+// Report lists what an import produced and what it could not convert
+type Report struct {
+	Files    []string
+	Warnings []string
+}
+
+func (r *Report) warn(format string, a ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, a...))
+}
@@ -0,0 +1,21 @@
+package importer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// doubleBraceVariablePattern matches {{var}} references used by Postman and IDE http clients
+var doubleBraceVariablePattern = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// convertTemplate rewrites {{var}} references to goful's {var} syntax
+func convertTemplate(s string, path string, report *Report) string {
+	return doubleBraceVariablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := strings.TrimSpace(match[2 : len(match)-2])
+		if strings.HasPrefix(name, "$") {
+			report.warn("%s: dynamic variable %s is not supported", path, name)
+			return match
+		}
+		return "{" + EnvKey(name) + "}"
+	})
+}
@@ -0,0 +1,226 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/envfile"
+	"goful/core/loader"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog/log"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlRequestFile mirrors model.YamlRequest field order when writing request files
+type yamlRequestFile struct {
+	Name     string            `yaml:"name"`
+	PrevReq  string            `yaml:"prev_req,omitempty"`
+	Url      string            `yaml:"url"`
+	Method   string            `yaml:"method"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	Query    model.Query       `yaml:"query,omitempty"`
+	Body     model.Body        `yaml:"body,omitempty"`
+	Insecure bool              `yaml:"insecure,omitempty"`
+}
+
+var ErrRequestExists = errors.New("file already exists")
+
+// MarshalYamlRequest renders a request in the same format the loader reads.
+func MarshalYamlRequest(yamlRequest *model.YamlRequest) (string, error) {
+	out := yamlRequestFile{
+		Name:     yamlRequest.Name,
+		PrevReq:  yamlRequest.PrevReq,
+		Url:      yamlRequest.Url,
+		Method:   yamlRequest.Method,
+		Headers:  yamlRequest.Headers.ToMap(),
+		Query:    yamlRequest.Query,
+		Body:     yamlRequest.Body,
+		Insecure: yamlRequest.Insecure,
+	}
+	if len(out.Headers) == 0 {
+		out.Headers = nil
+	}
+	var sb strings.Builder
+	encoder := yaml.NewEncoder(&sb)
+	encoder.SetIndent(2)
+	err := encoder.Encode(out)
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// SaveYamlRequest writes the request into a new file in root and returns the resulting mold.
+// Existing files are never overwritten.
+func SaveYamlRequest(root string, yamlRequest *model.YamlRequest) (model.RequestMold, error) {
+	return saveYamlRequest(root, "", yamlRequest)
+}
+
+// saveYamlRequest writes the request into dir, a directory relative to root, such as a folder of an imported
+// collection. The names in dir must already be sanitized.
+func saveYamlRequest(root string, dir string, yamlRequest *model.YamlRequest) (model.RequestMold, error) {
+	if yamlRequest.Name == "" {
+		return model.RequestMold{}, errors.New("request must have a name")
+	}
+
+	raw, err := MarshalYamlRequest(yamlRequest)
+	if err != nil {
+		return model.RequestMold{}, err
+	}
+	yamlRequest.Raw = raw
+
+	err = os.MkdirAll(filepath.Join(root, dir), 0755)
+	if err != nil {
+		return model.RequestMold{}, err
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("%s.yaml", sanitizeFilename(yamlRequest.Name)))
+	path := filepath.Join(root, filename)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return model.RequestMold{}, fmt.Errorf("%w: %s", ErrRequestExists, path)
+		}
+		return model.RequestMold{}, err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(raw)
+	if err != nil {
+		return model.RequestMold{}, err
+	}
+	log.Info().Msgf("Saved imported request %s to %s", yamlRequest.Name, path)
+
+	return model.RequestMold{
+		Yaml:        yamlRequest,
+		ContentType: "yaml",
+		Root:        root,
+		Filename:    filename,
+	}, nil
+}
+
+func sanitizeFilename(name string) string {
+	replacer := strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+	sanitized := strings.TrimSpace(replacer.Replace(name))
+	// names that are path segments on their own would refer to a directory, hidden ones would not be read
+	if sanitized == "" || sanitized == "." || sanitized == ".." {
+		return strings.Repeat("_", max(len(sanitized), 1))
+	}
+	if loader.IsHiddenDir(sanitized) {
+		return "_" + sanitized[1:]
+	}
+	return sanitized
+}
+
+type formField struct {
+	Name  string
+	Value string
+}
+
+// multipartBody renders text form fields as a multipart/form-data body with a fixed boundary
+func multipartBody(fields []formField) (string, string) {
+	const boundary = "------------------------goful"
+	var sb strings.Builder
+	for _, field := range fields {
+		sb.WriteString(fmt.Sprintf("--%s\r\n", boundary))
+		sb.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=\"%s\"\r\n\r\n", field.Name))
+		sb.WriteString(field.Value)
+		sb.WriteString("\r\n")
+	}
+	sb.WriteString(fmt.Sprintf("--%s--\r\n", boundary))
+	return sb.String(), fmt.Sprintf("multipart/form-data; boundary=%s", boundary)
+}
+
+// SaveProfile writes variables into a new .env.<name> file in root, or .env for the default profile.
+// Existing files are never overwritten.
+func SaveProfile(root string, name string, variables map[string]string) (string, error) {
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(root, profileFilename(name))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("%w: %s", ErrRequestExists, path)
+		}
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = file.Write(marshalEnv(variables))
+	if err != nil {
+		return "", err
+	}
+	log.Info().Msgf("Saved imported profile %s to %s", name, path)
+	return path, nil
+}
+
+// AddProfileVariable appends a variable to the profile file in root, creating the file when needed.
+// Variables the profile already defines are left unchanged, in which case false is returned.
+func AddProfileVariable(root string, name string, key string, value string) (string, bool, error) {
+	path := filepath.Join(root, profileFilename(name))
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return path, false, err
+	}
+	file := envfile.Parse(existing)
+	if len(existing) == 0 {
+		file = &envfile.File{}
+	}
+	if slices.Contains(file.Keys(), key) {
+		return path, false, nil
+	}
+	file.Set(key, value)
+
+	err = os.MkdirAll(root, 0755)
+	if err != nil {
+		return path, false, err
+	}
+	err = os.WriteFile(path, file.Bytes(), 0644)
+	if err != nil {
+		return path, false, err
+	}
+	return path, true, nil
+}
+
+// profileFilename is the file of profile name, an empty name is the default profile
+func profileFilename(name string) string {
+	if name == "" {
+		name = loader.DefaultProfileName
+	}
+	return loader.ProfileFilename(name)
+}
+
+// marshalEnv renders variables sorted by key, quoted the same way as profiles edited in goful
+func marshalEnv(variables map[string]string) []byte {
+	keys := make([]string, 0, len(variables))
+	for k := range variables {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	file := &envfile.File{}
+	for _, k := range keys {
+		file.Set(k, variables[k])
+	}
+	return file.Bytes()
+}
+
+// EnvKey converts a variable name to one godotenv accepts, i.e. one matching [A-Za-z0-9_.]
+func EnvKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '.' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -1,15 +1,90 @@
 package loader
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"goful/core/envcrypt"
 	"goful/core/model"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/joho/godotenv"
 )
 
+const (
+	DefaultProfileName = "default"
+	// localProfileSuffix marks files that override a profile on one machine only and are kept out of version control
+	localProfileSuffix = ".local"
+	// encryptedProfileSuffix marks files encrypted with envcrypt, they are decrypted in memory when read
+	encryptedProfileSuffix = ".enc"
+)
+
+var (
+	decryptedMutex sync.Mutex
+	decrypted      = make(map[[sha256.Size]byte][]byte)
+)
+
+// ProfileFilename returns the name of the file holding the variables of profile name
+func ProfileFilename(name string) string {
+	if name == DefaultProfileName {
+		return ".env"
+	}
+	return ".env." + name
+}
+
+// LocalProfileFilename returns the name of the file overriding the variables of profile name locally
+func LocalProfileFilename(name string) string {
+	return ProfileFilename(name) + localProfileSuffix
+}
+
+// EncryptedProfileFilename returns the name of the encrypted file holding the variables of profile name
+func EncryptedProfileFilename(name string) string {
+	return ProfileFilename(name) + encryptedProfileSuffix
+}
+
+// ValidateProfileName returns why name can not be the name of a new profile, the file of such a profile would be
+// read as the default profile, as a local override or as an encrypted file
+func ValidateProfileName(name string) error {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "":
+		return errors.New("profile name is empty")
+	case lower == DefaultProfileName:
+		return fmt.Errorf("%s is the name of the profile in .env", DefaultProfileName)
+	case lower == "local":
+		return errors.New(".env.local overrides the default profile locally")
+	case strings.HasSuffix(lower, localProfileSuffix), strings.HasSuffix(lower, encryptedProfileSuffix):
+		return fmt.Errorf("profile names can not end in %s or %s", localProfileSuffix, encryptedProfileSuffix)
+	}
+	return nil
+}
+
+// parseProfileFilename returns the profile of a .env file and whether the file is a local override of it
+// and whether it is encrypted
+func parseProfileFilename(filename string) (name string, local bool, encrypted bool, ok bool) {
+	if filename != ".env" && !strings.HasPrefix(filename, ".env.") {
+		return "", false, false, false
+	}
+	filename, encrypted = strings.CutSuffix(filename, encryptedProfileSuffix)
+	name = strings.TrimPrefix(strings.TrimPrefix(filename, ".env"), ".")
+	if name == "local" {
+		return DefaultProfileName, true, encrypted, true
+	}
+	name, local = strings.CutSuffix(name, localProfileSuffix)
+	if name == "" {
+		name = DefaultProfileName
+	}
+	return name, local, encrypted, true
+}
+
+// ReadProfiles reads the profiles of root with the variables of their own files. Local overrides are left out,
+// see ResolveProfile for the variables a profile ends up with. Encrypted files are decrypted in memory with the
+// passphrase of envcrypt.Passphrase.
 func ReadProfiles(root string) ([]model.Profile, error) {
 	var profileSlice []model.Profile
 	maxDepth := 0
@@ -18,28 +93,19 @@ func ReadProfiles(root string) ([]model.Profile, error) {
 			return err
 		}
 
-		if info.IsDir() && strings.Count(path, string(os.PathSeparator)) > maxDepth {
+		if info.IsDir() && depth(root, path) > maxDepth {
 			return fs.SkipDir
 		}
 
-		filename := info.Name()
-
-		if strings.HasPrefix(filename, ".env") {
-
-			file, err := os.Open(path)
-			if err != nil {
-				return err
+		profileName, local, encrypted, ok := parseProfileFilename(info.Name())
+		if ok && !local && !info.IsDir() {
+			if _, err := os.Stat(filepath.Join(root, ProfileFilename(profileName))); encrypted && err == nil {
+				// read when the plain file is walked, which fails as a profile may have only one of them
+				return nil
 			}
-
-			envFile, err := godotenv.Parse(file)
-			defer file.Close()
+			envFile, filename, err := readProfileFile(root, ProfileFilename(profileName))
 			if err != nil {
-				return err
-			}
-			profileName := "default"
-			splits := strings.Split(filename, ".")
-			if len(splits) > 2 {
-				profileName = splits[len(splits)-1]
+				return fmt.Errorf("failed to read %s: %w", filename, err)
 			}
 
 			profile := model.Profile{
@@ -59,3 +125,53 @@ func ReadProfiles(root string) ([]model.Profile, error) {
 
 	return profileSlice, nil
 }
+
+// readProfileFile reads the variables of a profile file of root, or of its encrypted counterpart when only that
+// exists. The name of the file read is returned.
+func readProfileFile(root string, filename string) (map[string]string, string, error) {
+	_, plainErr := os.Stat(filepath.Join(root, filename))
+	_, encryptedErr := os.Stat(filepath.Join(root, filename+encryptedProfileSuffix))
+	if plainErr == nil && encryptedErr == nil {
+		return nil, filename, fmt.Errorf("both %s and %s exist, remove one of them", filename, filename+encryptedProfileSuffix)
+	}
+	if errors.Is(plainErr, os.ErrNotExist) && encryptedErr == nil {
+		filename += encryptedProfileSuffix
+		variables, err := readEncryptedEnvFile(filepath.Join(root, filename))
+		return variables, filename, err
+	}
+	variables, err := readEnvFile(filepath.Join(root, filename))
+	return variables, filename, err
+}
+
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return godotenv.Parse(file)
+}
+
+func readEncryptedEnvFile(path string) (map[string]string, error) {
+	ciphertext, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	passphrase, err := envcrypt.Passphrase()
+	if err != nil {
+		return nil, err
+	}
+	// decrypting is slow by design, an unchanged file is decrypted once
+	key := sha256.Sum256(append([]byte(passphrase+"\x00"), ciphertext...))
+	decryptedMutex.Lock()
+	defer decryptedMutex.Unlock()
+	plaintext, ok := decrypted[key]
+	if !ok {
+		plaintext, passphrase, err = envcrypt.Open(ciphertext, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		decrypted[sha256.Sum256(append([]byte(passphrase+"\x00"), ciphertext...))] = plaintext
+	}
+	return godotenv.Parse(bytes.NewReader(plaintext))
+}
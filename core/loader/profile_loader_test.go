@@ -47,3 +47,22 @@ func TestReadProfiles(t *testing.T) {
 	}
 
 }
+
+func TestValidateProfileName(t *testing.T) {
+	tests := map[string]bool{
+		"staging":       true,
+		"localhost":     true,
+		"defaults":      true,
+		"":              false,
+		"default":       false,
+		"local":         false,
+		"Local":         false,
+		"staging.local": false,
+		"staging.enc":   false,
+	}
+	for name, wanted := range tests {
+		if got := ValidateProfileName(name) == nil; got != wanted {
+			t.Errorf("ValidateProfileName(%q): got valid %v wanted %v", name, got, wanted)
+		}
+	}
+}
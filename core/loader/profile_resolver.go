@@ -0,0 +1,129 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	SourceEnvironment = "environment"
+	SourceOverride    = "--var"
+)
+
+var ErrProfileNotFound = errors.New("profile not found")
+
+// Layer is a set of variables of a profile and where they were read from
+type Layer struct {
+	Source    string
+	Variables map[string]string
+}
+
+// ResolvedVariable is the final value of a variable. Overridden lists the sources of the values it replaced,
+// from the lowest precedence to the highest.
+type ResolvedVariable struct {
+	Key        string
+	Value      string
+	Source     string
+	Overridden []string
+}
+
+// ProfileLayers returns the layers of profile name from the lowest precedence to the highest:
+// the default profile, the file of the profile, its local file, the process environment and overrides.
+// The process environment only overrides variables defined in the files. Missing files are skipped, but the file of
+// a profile other than the default one has to exist. Any of the files may be encrypted, see ReadProfiles.
+func ProfileLayers(root string, name string, overrides map[string]string) ([]Layer, error) {
+	if name == "" {
+		name = DefaultProfileName
+	}
+	filenames := []string{ProfileFilename(DefaultProfileName)}
+	if name != DefaultProfileName {
+		_, plainErr := os.Stat(filepath.Join(root, ProfileFilename(name)))
+		_, encryptedErr := os.Stat(filepath.Join(root, EncryptedProfileFilename(name)))
+		if errors.Is(plainErr, os.ErrNotExist) && errors.Is(encryptedErr, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s in workspace %s", ErrProfileNotFound, name, root)
+		}
+		filenames = append(filenames, ProfileFilename(name))
+	}
+	filenames = append(filenames, LocalProfileFilename(name))
+
+	var layers []Layer
+	defined := make(map[string]bool)
+	for _, filename := range filenames {
+		variables, filename, err := readProfileFile(root, filename)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+		for k := range variables {
+			defined[k] = true
+		}
+		layers = append(layers, Layer{Source: filename, Variables: variables})
+	}
+
+	environment := make(map[string]string)
+	for k := range defined {
+		if v, ok := os.LookupEnv(k); ok {
+			environment[k] = v
+		}
+	}
+	if len(environment) > 0 {
+		layers = append(layers, Layer{Source: SourceEnvironment, Variables: environment})
+	}
+	if len(overrides) > 0 {
+		layers = append(layers, Layer{Source: SourceOverride, Variables: overrides})
+	}
+	return layers, nil
+}
+
+// ResolveLayers merges the layers so that later layers override earlier ones. Variables are sorted by key.
+func ResolveLayers(layers []Layer) []ResolvedVariable {
+	resolved := make(map[string]*ResolvedVariable)
+	for _, layer := range layers {
+		for k, v := range layer.Variables {
+			variable, ok := resolved[k]
+			if !ok {
+				resolved[k] = &ResolvedVariable{Key: k, Value: v, Source: layer.Source}
+				continue
+			}
+			variable.Overridden = append(variable.Overridden, variable.Source)
+			variable.Value = v
+			variable.Source = layer.Source
+		}
+	}
+
+	var variables []ResolvedVariable
+	for _, v := range resolved {
+		variables = append(variables, *v)
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Key < variables[j].Key
+	})
+	return variables
+}
+
+// ResolveProfile reads profile name with the variables of all its layers, see ProfileLayers, and its defaults,
+// see ReadDefaults
+func ResolveProfile(root string, name string, overrides map[string]string) (model.Profile, error) {
+	if name == "" {
+		name = DefaultProfileName
+	}
+	layers, err := ProfileLayers(root, name, overrides)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	variables := make(map[string]string)
+	for _, v := range ResolveLayers(layers) {
+		variables[v.Key] = v.Value
+	}
+	defaults, err := ReadDefaults(root, name)
+	if err != nil {
+		return model.Profile{}, err
+	}
+	return model.Profile{Name: name, Variables: variables, Defaults: defaults}, nil
+}
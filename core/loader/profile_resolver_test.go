@@ -0,0 +1,250 @@
+package loader
+
+import (
+	"errors"
+	"goful/core/envcrypt"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeProfileFiles(t *testing.T, files map[string]string) string {
+	root := t.TempDir()
+	for filename, content := range files {
+		err := os.WriteFile(filepath.Join(root, filename), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("did not expect error %v", err)
+		}
+	}
+	return root
+}
+
+func TestResolveProfile(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env":                  "domain=foobar.com\nuser=jane\ntoken=default\nscheme=https\n",
+		".env.production":       "domain=foobarprod.com\ntoken=production\n",
+		".env.production.local": "token=local\n",
+		".env.local":            "user=local\n",
+	})
+	t.Setenv("token", "environment")
+	t.Setenv("GOFUL_UNRELATED", "not a variable")
+
+	layers, err := ProfileLayers(root, "production", map[string]string{"scheme": "http"})
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	var sources []string
+	for _, l := range layers {
+		sources = append(sources, l.Source)
+	}
+	expectedSources := []string{".env", ".env.production", ".env.production.local", SourceEnvironment, SourceOverride}
+	if !cmp.Equal(sources, expectedSources) {
+		t.Errorf("got\n%v\nwanted\n%v", sources, expectedSources)
+	}
+
+	expected := []ResolvedVariable{
+		{Key: "domain", Value: "foobarprod.com", Source: ".env.production", Overridden: []string{".env"}},
+		{Key: "scheme", Value: "http", Source: SourceOverride, Overridden: []string{".env"}},
+		{Key: "token", Value: "environment", Source: SourceEnvironment, Overridden: []string{".env", ".env.production", ".env.production.local"}},
+		{Key: "user", Value: "jane", Source: ".env"},
+	}
+	resolved := ResolveLayers(layers)
+	if !cmp.Equal(resolved, expected) {
+		t.Errorf("got\n%v\nwanted\n%v", resolved, expected)
+	}
+
+	profile, err := ResolveProfile(root, "production", map[string]string{"scheme": "http"})
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	expectedVariables := map[string]string{"domain": "foobarprod.com", "scheme": "http", "token": "environment", "user": "jane"}
+	if profile.Name != "production" || !cmp.Equal(profile.Variables, expectedVariables) {
+		t.Errorf("got\n%v\nwanted\n%v", profile, expectedVariables)
+	}
+}
+
+func TestResolveDefaultProfile(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env":       "domain=foobar.com\nuser=jane\n",
+		".env.local": "user=local\n",
+	})
+
+	profile, err := ResolveProfile(root, "", nil)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	expected := map[string]string{"domain": "foobar.com", "user": "local"}
+	if profile.Name != DefaultProfileName || !cmp.Equal(profile.Variables, expected) {
+		t.Errorf("got\n%v\nwanted\n%v", profile, expected)
+	}
+
+	empty, err := ResolveProfile(t.TempDir(), DefaultProfileName, nil)
+	if err != nil || len(empty.Variables) != 0 {
+		t.Errorf("got %v and error %v, wanted an empty default profile", empty, err)
+	}
+}
+
+func TestResolveMissingProfile(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{".env": "domain=foobar.com\n"})
+
+	_, err := ResolveProfile(root, "staging", nil)
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("got %v, wanted %v", err, ErrProfileNotFound)
+	}
+}
+
+func TestReadProfilesSkipsLocalFiles(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env":               "domain=foobar.com\n",
+		".env.local":         "domain=localhost\n",
+		".env.staging":       "domain=staging.foobar.com\n",
+		".env.staging.local": "domain=localhost\n",
+		".envrc":             "export FOO=bar\n",
+	})
+
+	profiles, err := ReadProfiles(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	var names []string
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	if !cmp.Equal(names, []string{"default", "staging"}) {
+		t.Errorf("got\n%v\nwanted\n%v", names, []string{"default", "staging"})
+	}
+}
+
+func writeEncryptedProfileFile(t *testing.T, root string, filename string, content string, passphrase string) {
+	ciphertext, err := envcrypt.Encrypt([]byte(content), passphrase)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, filename), ciphertext, 0644)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+}
+
+func TestResolveEncryptedProfile(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env":               "domain=foobar.com\ntoken=default\n",
+		".env.staging.local": "user=local\n",
+	})
+	writeEncryptedProfileFile(t, root, ".env.staging.enc", "domain=staging.foobar.com\ntoken=staging\n", "correct horse")
+	t.Setenv(envcrypt.PassphraseEnv, "correct horse")
+
+	layers, err := ProfileLayers(root, "staging", nil)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	var sources []string
+	for _, l := range layers {
+		sources = append(sources, l.Source)
+	}
+	expectedSources := []string{".env", ".env.staging.enc", ".env.staging.local"}
+	if !cmp.Equal(sources, expectedSources) {
+		t.Errorf("got\n%v\nwanted\n%v", sources, expectedSources)
+	}
+	expected := map[string]string{"domain": "staging.foobar.com", "token": "staging", "user": "local"}
+	if variables := resolvedValues(layers); !cmp.Equal(variables, expected) {
+		t.Errorf("got\n%v\nwanted\n%v", variables, expected)
+	}
+
+	profiles, err := ReadProfiles(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	var names []string
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+	if !cmp.Equal(names, []string{"default", "staging"}) {
+		t.Errorf("got\n%v\nwanted\n%v", names, []string{"default", "staging"})
+	}
+
+	t.Setenv(envcrypt.PassphraseEnv, "battery staple")
+	_, err = ProfileLayers(root, "staging", nil)
+	if !errors.Is(err, envcrypt.ErrWrongPassphrase) {
+		t.Errorf("got %v, wanted %v", err, envcrypt.ErrWrongPassphrase)
+	}
+}
+
+func TestReadProfilesWithPlainAndEncryptedFile(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env.staging":     "domain=staging.foobar.com\n",
+		".env.staging.enc": "not read",
+	})
+
+	_, err := ReadProfiles(root)
+	if err == nil {
+		t.Errorf("expected error when a profile has both a plain and an encrypted file")
+	}
+	_, err = ProfileLayers(root, "staging", nil)
+	if err == nil {
+		t.Errorf("expected error when a profile has both a plain and an encrypted file")
+	}
+}
+
+func resolvedValues(layers []Layer) map[string]string {
+	values := make(map[string]string)
+	for _, v := range ResolveLayers(layers) {
+		values[v.Key] = v.Value
+	}
+	return values
+}
+
+func TestResolveProfileDefaults(t *testing.T) {
+	root := writeProfileFiles(t, map[string]string{
+		".env":         "host=foobar.com\n",
+		".env.staging": "host=staging.foobar.com\n",
+		WorkspaceConfigFilename: `theme:
+  syntax: dracula
+defaults:
+  base_url: https://{host}/api
+  headers:
+    Accept: application/json
+  query:
+    apiVersion: "2"
+profiles:
+  staging:
+    defaults:
+      headers:
+        X-Debug: "true"
+      query:
+        apiVersion: "3"
+`,
+	})
+
+	profile, err := ResolveProfile(root, "staging", nil)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wanted := model.Defaults{
+		BaseUrl: "https://{host}/api",
+		Headers: model.Headers{"Accept": {"application/json"}, "X-Debug": {"true"}},
+		Query:   model.Query{"apiVersion": {"3"}},
+	}
+	if !cmp.Equal(profile.Defaults, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", profile.Defaults, wanted)
+	}
+
+	profile, err = ResolveProfile(root, "default", nil)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if profile.Defaults.Headers["X-Debug"] != nil || profile.Defaults.Query["apiVersion"][0] != "2" {
+		t.Errorf("expected defaults of the workspace only, got %v", profile.Defaults)
+	}
+}
@@ -11,64 +11,34 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// ReadRequests reads the requests in root and its subdirectories, their filenames are relative to root.
+// Hidden directories, such as .git or the history in .goful, are skipped.
 func ReadRequests(root string) ([]model.RequestMold, error) {
 	var requestSlice []model.RequestMold
-	maxDepth := 0
 	err := filepath.WalkDir(root, func(path string, info os.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.IsDir() && strings.Count(path, string(os.PathSeparator)) > maxDepth {
+		if info.IsDir() && path != root && IsHiddenDir(info.Name()) {
 			return fs.SkipDir
 		}
 
-		filename := info.Name()
-		log.Debug().Msgf("Walk crossed a file %s", filename)
-		var extension = filepath.Ext(filename)
-
-		switch {
-		case extension == ".yaml" || extension == ".yml":
-
-			file, err := os.ReadFile(path)
-			if err != nil {
-				log.Error().Err(err).Msgf("Failed to read %s", path)
-				return nil
-			}
-			yamlRequest := &model.YamlRequest{}
-			err = yaml.Unmarshal(file, yamlRequest)
-			if err != nil {
-				return nil
-			}
-			yamlRequest.Raw = string(file)
-			if yamlRequest.Name != "" {
-				request := model.RequestMold{
-					Yaml:        yamlRequest,
-					ContentType: "yaml",
-					Root:        root,
-					Filename:    filename,
-				}
-				requestSlice = append(requestSlice, request)
-			}
-
-		case extension == ".star":
-
-			file, err := os.ReadFile(path)
-			if err != nil {
-				log.Error().Err(err).Msgf("Failed to read %s", path)
-				return nil
-			}
-			starlarkRequest := &model.StarlarkRequest{
-				Script: string(file),
-			}
-			request := model.RequestMold{
-				Starlark:    starlarkRequest,
-				ContentType: "star",
-				Root:        root,
-				Filename:    filename,
-			}
-			requestSlice = append(requestSlice, request)
-
+		log.Debug().Msgf("Walk crossed a file %s", path)
+		if info.IsDir() {
+			return nil
+		}
+		filename, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		request, err := ReadRequest(root, filename)
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to read %s", path)
+			return nil
+		}
+		if request != nil {
+			requestSlice = append(requestSlice, *request)
 		}
 
 		return nil
@@ -80,3 +50,68 @@ func ReadRequests(root string) ([]model.RequestMold, error) {
 
 	return requestSlice, nil
 }
+
+// IsHiddenDir tells whether the directory name is hidden, its requests are not read
+func IsHiddenDir(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+// WorkspaceConfigFilename is the config file of a workspace, which is not a request despite its extension
+const WorkspaceConfigFilename = ".goful.yaml"
+
+// IsRequestFile tells whether filename has the extension of a yaml or starlark request
+func IsRequestFile(filename string) bool {
+	if filepath.Base(filename) == WorkspaceConfigFilename {
+		return false
+	}
+	switch filepath.Ext(filename) {
+	case ".yaml", ".yml", ".star":
+		return true
+	}
+	return false
+}
+
+// ReadRequest reads the request in file filename, a path relative to root. It returns nil without an error when the file is not a
+// request, such as yaml without a name.
+func ReadRequest(root string, filename string) (*model.RequestMold, error) {
+	if !IsRequestFile(filename) {
+		return nil, nil
+	}
+	file, err := os.ReadFile(filepath.Join(root, filename))
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(filename) == ".star" {
+		return &model.RequestMold{
+			Starlark: &model.StarlarkRequest{
+				Script: string(file),
+			},
+			ContentType: "star",
+			Root:        root,
+			Filename:    filename,
+		}, nil
+	}
+
+	yamlRequest := &model.YamlRequest{}
+	err = yaml.Unmarshal(file, yamlRequest)
+	if err != nil || yamlRequest.Name == "" {
+		return nil, nil
+	}
+	yamlRequest.Raw = string(file)
+	return &model.RequestMold{
+		Yaml:        yamlRequest,
+		ContentType: "yaml",
+		Root:        root,
+		Filename:    filename,
+	}, nil
+}
+
+// depth returns how many directories deep path is below root
+func depth(root string, path string) int {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return 0
+	}
+	return strings.Count(rel, string(os.PathSeparator)) + 1
+}
@@ -2,6 +2,8 @@ package loader
 
 import (
 	"goful/core/model"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -94,7 +96,7 @@ body: >
 				t.Errorf("structs are not equal!\ngot\n%v\nwanted\n%v", r, w)
 			}
 		}
-		if !cmp.Equal(request.Raw, wantedRequest.Raw) {
+		if !cmp.Equal(request.Raw(), wantedRequest.Raw()) {
 			t.Errorf("structs are not equal!\ngot\n%v\nwanted\n%v", request, wantedRequest)
 		}
 		if !cmp.Equal(request.ContentType, wantedRequest.ContentType) {
@@ -118,3 +120,52 @@ func TestReadRequestsWithInvalidRoot(t *testing.T) {
 	}
 
 }
+
+func TestReadRequestsInSubdirectories(t *testing.T) {
+	root := t.TempDir()
+	request := []byte("name: get user\nurl: foobar.com\nmethod: GET\n")
+	for _, dir := range []string{filepath.Join("users", "admin"), ".goful"} {
+		err := os.MkdirAll(filepath.Join(root, dir), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(root, dir, "get user.yaml"), request, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests, err := ReadRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	// hidden directories are skipped
+	wantedFilename := filepath.Join("users", "admin", "get user.yaml")
+	if len(requests) != 1 || requests[0].Filename != wantedFilename || requests[0].Root != root {
+		t.Errorf("got %v, wanted %s in %s", requests, wantedFilename, root)
+	}
+}
+
+func TestReadRequest(t *testing.T) {
+	request, err := ReadRequest("testdata", "yaml_request.yaml")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	if request == nil || request.Name() != "yaml_request" || request.Root != "testdata" {
+		t.Errorf("got %v, wanted yaml_request in testdata", request)
+	}
+
+	for _, filename := range []string{"some_trash.yaml", "some_trash.txt", WorkspaceConfigFilename} {
+		request, err := ReadRequest("testdata", filename)
+		if err != nil || request != nil {
+			t.Errorf("got %v and error %v, wanted no request from %s", request, err, filename)
+		}
+	}
+
+	_, err = ReadRequest("testdata", "non_existent.yaml")
+	if err == nil {
+		t.Errorf("did expect error")
+	}
+}
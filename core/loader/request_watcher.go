@@ -0,0 +1,167 @@
+package loader
+
+import (
+	"goful/core/model"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// settleDelay groups the bursts of events editors produce when saving, e.g. write to a temp file and rename
+const settleDelay = 100 * time.Millisecond
+
+// RequestChange is a request file that was created, modified or removed under the watched root.
+// Filename is relative to the root. Mold is nil when the file was removed or is no longer a request.
+type RequestChange struct {
+	Filename string
+	Mold     *model.RequestMold
+}
+
+// RequestWatcher reports changes to the request files of a workspace and its subdirectories. Hidden directories are
+// not watched, in line with ReadRequests.
+type RequestWatcher struct {
+	Changes <-chan []RequestChange
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// WatchRequests starts watching root. Changed files are read again and sent to Changes in batches.
+// Changes is closed when the watcher is closed.
+func WatchRequests(root string) (*RequestWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	err = watchDir(watcher, root, root, files)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	changes := make(chan []RequestChange)
+	done := make(chan struct{})
+	go watchRequests(root, watcher, files, changes, done)
+	return &RequestWatcher{Changes: changes, watcher: watcher, done: done}, nil
+}
+
+func (w *RequestWatcher) Close() error {
+	close(w.done)
+	return w.watcher.Close()
+}
+
+// watchDir watches dir and its subdirectories, which fsnotify does not do on its own. The request files found in
+// them are added to files.
+func watchDir(watcher *fsnotify.Watcher, root string, dir string, files map[string]bool) error {
+	return filepath.WalkDir(dir, func(path string, info os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if filename, err := filepath.Rel(root, path); err == nil && IsRequestFile(filename) {
+				files[filename] = true
+			}
+			return nil
+		}
+		if path != root && IsHiddenDir(info.Name()) {
+			return fs.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// watchRequests sends the changes of the request files, files are the known request files to tell which of them are
+// gone when their directory is removed or moved
+func watchRequests(root string, watcher *fsnotify.Watcher, files map[string]bool, changes chan<- []RequestChange, done <-chan struct{}) {
+	defer close(changes)
+
+	pending := make(map[string]bool)
+	var settled <-chan time.Time
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			filename, err := filepath.Rel(root, event.Name)
+			if err != nil {
+				continue
+			}
+			changed := false
+			if info, err := os.Stat(event.Name); err == nil && info.IsDir() && event.Has(fsnotify.Create) {
+				// files created before the new directory is watched have no events of their own
+				created := make(map[string]bool)
+				err = watchDir(watcher, root, event.Name, created)
+				if err != nil {
+					log.Error().Err(err).Msgf("Failed to watch %s", event.Name)
+				}
+				for f := range created {
+					pending[f] = true
+					changed = true
+				}
+			}
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				// the watch of a moved directory would report its files under the old path
+				watcher.Remove(event.Name)
+				for f := range files {
+					if strings.HasPrefix(f, filename+string(os.PathSeparator)) {
+						pending[f] = true
+						changed = true
+					}
+				}
+			}
+			if IsRequestFile(filename) {
+				log.Debug().Msgf("Request file %s changed: %s", filename, event.Op)
+				pending[filename] = true
+				changed = true
+			}
+			if changed {
+				settled = time.After(settleDelay)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Msgf("Error occurred while watching %s", root)
+		case <-settled:
+			settled = nil
+			var filenames []string
+			for filename := range pending {
+				filenames = append(filenames, filename)
+			}
+			sort.Strings(filenames)
+			pending = make(map[string]bool)
+
+			var batch []RequestChange
+			for _, filename := range filenames {
+				mold, err := ReadRequest(root, filename)
+				if err != nil {
+					// removed files can not be read, they are reported without a mold
+					log.Debug().Err(err).Msgf("Could not read changed file %s", filename)
+				}
+				if mold != nil {
+					files[filename] = true
+				} else {
+					delete(files, filename)
+				}
+				batch = append(batch, RequestChange{Filename: filename, Mold: mold})
+			}
+			select {
+			case changes <- batch:
+			case <-done:
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchRequests(t *testing.T) {
+	root := t.TempDir()
+	watcher, err := WatchRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	path := filepath.Join(root, "users.yaml")
+	err = os.WriteFile(path, []byte("name: users\nurl: foobar.com\nmethod: GET\n"), 0644)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	os.WriteFile(filepath.Join(root, "notes.txt"), []byte("not a request"), 0644)
+
+	changes := nextChanges(t, watcher)
+	if len(changes) != 1 || changes[0].Filename != "users.yaml" || changes[0].Mold == nil {
+		t.Errorf("got %v, wanted users.yaml", changes)
+		return
+	}
+	if changes[0].Mold.Url() != "foobar.com" || changes[0].Mold.Root != root {
+		t.Errorf("got %v, wanted url foobar.com under %s", changes[0].Mold, root)
+	}
+
+	os.Remove(path)
+	changes = nextChanges(t, watcher)
+	if len(changes) != 1 || changes[0].Filename != "users.yaml" || changes[0].Mold != nil {
+		t.Errorf("got %v, wanted removed users.yaml", changes)
+	}
+}
+
+func TestWatchRequestsInSubdirectories(t *testing.T) {
+	root := t.TempDir()
+	err := os.Mkdir(filepath.Join(root, "users"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher, err := WatchRequests(root)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	request := []byte("name: users\nurl: foobar.com\nmethod: GET\n")
+	filename := filepath.Join("users", "list.yaml")
+	os.WriteFile(filepath.Join(root, filename), request, 0644)
+	changes := nextChanges(t, watcher)
+	if len(changes) != 1 || changes[0].Filename != filename || changes[0].Mold == nil {
+		t.Errorf("got %v, wanted %s", changes, filename)
+		return
+	}
+
+	// directories created later are watched as well
+	created := filepath.Join("admin", "delete.yaml")
+	os.Mkdir(filepath.Join(root, "admin"), 0755)
+	os.WriteFile(filepath.Join(root, created), request, 0644)
+	changes = nextChanges(t, watcher)
+	if len(changes) != 1 || changes[0].Filename != created || changes[0].Mold == nil {
+		t.Errorf("got %v, wanted %s", changes, created)
+		return
+	}
+
+	// the files of a removed directory are reported as removed
+	os.RemoveAll(filepath.Join(root, "users"))
+	changes = nextChanges(t, watcher)
+	if len(changes) != 1 || changes[0].Filename != filename || changes[0].Mold != nil {
+		t.Errorf("got %v, wanted removed %s", changes, filename)
+	}
+}
+
+func nextChanges(t *testing.T, watcher *RequestWatcher) []RequestChange {
+	select {
+	case changes := <-watcher.Changes:
+		return changes
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for changes")
+		return nil
+	}
+}
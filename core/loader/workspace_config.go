@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"goful/core/model"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceConfig is the part of the workspace config file read here, the rest is configuration read by viper.
+// Viper lowercases keys, which would change header names and query parameters.
+type workspaceConfig struct {
+	Defaults model.Defaults `yaml:"defaults"`
+	Profiles map[string]struct {
+		Defaults model.Defaults `yaml:"defaults"`
+	} `yaml:"profiles"`
+}
+
+// ReadDefaults reads the defaults of the workspace merged with the defaults of profile name from the workspace
+// config file, e.g.
+//
+//	defaults:
+//	  base_url: https://{host}/api
+//	  headers:
+//	    Accept: application/json
+//	profiles:
+//	  staging:
+//	    defaults:
+//	      query:
+//	        debug: "true"
+//
+// A workspace without a config file has no defaults.
+func ReadDefaults(root string, name string) (model.Defaults, error) {
+	data, err := os.ReadFile(filepath.Join(root, WorkspaceConfigFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return model.Defaults{}, nil
+	}
+	if err != nil {
+		return model.Defaults{}, err
+	}
+	var config workspaceConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return model.Defaults{}, fmt.Errorf("failed to read defaults of %s: %w", WorkspaceConfigFilename, err)
+	}
+	return config.Defaults.Merge(config.Profiles[name].Defaults), nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+type QueryValues []string
+
+// Query holds query parameters, a parameter may be repeated by giving it a list of values
+type Query map[string]QueryValues
+
+func (queryValues *QueryValues) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err
+		}
+		*queryValues = values
+		return nil
+	}
+	*queryValues = QueryValues{node.Value}
+	return nil
+}
+
+// Defaults apply to every request built with a profile, values of the requests take precedence
+type Defaults struct {
+	// BaseUrl is prepended to relative request urls
+	BaseUrl string  `yaml:"base_url"`
+	Headers Headers `yaml:"headers"`
+	Query   Query   `yaml:"query"`
+}
+
+// Merge returns the defaults overridden by override. Headers are matched case-insensitively.
+func (d Defaults) Merge(override Defaults) Defaults {
+	merged := Defaults{BaseUrl: d.BaseUrl}
+	if override.BaseUrl != "" {
+		merged.BaseUrl = override.BaseUrl
+	}
+	if d.Headers != nil || override.Headers != nil {
+		merged.Headers = make(Headers)
+		for _, headers := range []Headers{d.Headers, override.Headers} {
+			for name, values := range headers {
+				merged.Headers[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+	}
+	if d.Query != nil || override.Query != nil {
+		merged.Query = make(Query)
+		for _, query := range []Query{d.Query, override.Query} {
+			for name, values := range query {
+				merged.Query[name] = values
+			}
+		}
+	}
+	return merged
+}
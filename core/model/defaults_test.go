@@ -0,0 +1,47 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDefaultsMerge(t *testing.T) {
+	workspace := Defaults{
+		BaseUrl: "https://foobar.com",
+		Headers: Headers{"accept": {"application/json"}, "User-Agent": {"goful"}},
+		Query:   Query{"debug": {"false"}, "api-version": {"1"}},
+	}
+	profile := Defaults{
+		Headers: Headers{"Accept": {"text/plain"}},
+		Query:   Query{"debug": {"true"}},
+	}
+
+	wanted := Defaults{
+		BaseUrl: "https://foobar.com",
+		Headers: Headers{"Accept": {"text/plain"}, "User-Agent": {"goful"}},
+		Query:   Query{"debug": {"true"}, "api-version": {"1"}},
+	}
+	merged := workspace.Merge(profile)
+	if !cmp.Equal(merged, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", merged, wanted)
+	}
+
+	if merged := (Defaults{}).Merge(Defaults{}); !cmp.Equal(merged, Defaults{}) {
+		t.Errorf("got\n%v\nwanted\n%v", merged, Defaults{})
+	}
+}
+
+func TestQueryUnmarshalYAML(t *testing.T) {
+	var query Query
+	err := yaml.Unmarshal([]byte("page: 1\ntag:\n  - a\n  - b\nq: a,b\n"), &query)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+		return
+	}
+	wanted := Query{"page": {"1"}, "tag": {"a", "b"}, "q": {"a,b"}}
+	if !cmp.Equal(query, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", query, wanted)
+	}
+}
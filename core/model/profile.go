@@ -4,4 +4,6 @@ type Profile struct {
 	Name      string
 	Variables map[string]string
 	Raw       string
+	// Defaults of the workspace and the profile, applied to every request built with the profile
+	Defaults Defaults
 }
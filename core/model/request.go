@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -10,10 +11,11 @@ import (
 )
 
 type Request struct {
-	Url     string
-	Method  string
-	Headers Headers
-	Body    Body
+	Url      string
+	Method   string
+	Headers  Headers
+	Body     Body
+	Insecure bool
 }
 
 type RequestMold struct {
@@ -25,13 +27,15 @@ type RequestMold struct {
 }
 
 type YamlRequest struct {
-	Name    string
-	PrevReq string  `yaml:"prev_req"`
-	Url     string  `yaml:"url"`
-	Method  string  `yaml:"method"`
-	Headers Headers `yaml:"headers"`
-	Body    Body    `yaml:"body"`
-	Raw     string
+	Name     string
+	PrevReq  string  `yaml:"prev_req"`
+	Url      string  `yaml:"url"`
+	Method   string  `yaml:"method"`
+	Headers  Headers `yaml:"headers"`
+	Query    Query   `yaml:"query"`
+	Body     Body    `yaml:"body"`
+	Insecure bool    `yaml:"insecure"`
+	Raw      string
 }
 
 // FIXME has the same value as RequestMold Raw
@@ -95,7 +99,8 @@ func (r *RequestMold) DeleteFromFS() bool {
 
 func (r *RequestMold) Rename(newName string) bool {
 	oldName := r.Name()
-	r.Filename = strings.ReplaceAll(r.Filename, oldName, newName)
+	// the directory of the file is kept, even when its name contains the name of the request
+	r.Filename = filepath.Join(filepath.Dir(r.Filename), strings.ReplaceAll(filepath.Base(r.Filename), oldName, newName))
 	if r.Yaml != nil {
 		r.Yaml.Name = newName
 		pattern := regexp.MustCompile(`(?mU)^name:(.*)$`)
@@ -128,13 +133,15 @@ func (r *RequestMold) Clone() RequestMold {
 
 	if r.Yaml != nil {
 		yamlRequest := YamlRequest{
-			Name:    r.Yaml.Name,
-			PrevReq: r.Yaml.PrevReq,
-			Url:     r.Yaml.Url,
-			Method:  r.Yaml.Method,
-			Headers: r.Yaml.Headers,
-			Body:    r.Yaml.Body,
-			Raw:     r.Yaml.Raw,
+			Name:     r.Yaml.Name,
+			PrevReq:  r.Yaml.PrevReq,
+			Url:      r.Yaml.Url,
+			Method:   r.Yaml.Method,
+			Headers:  r.Yaml.Headers,
+			Query:    r.Yaml.Query,
+			Body:     r.Yaml.Body,
+			Insecure: r.Yaml.Insecure,
+			Raw:      r.Yaml.Raw,
 		}
 		copy.Yaml = &yamlRequest
 	} else if r.Starlark != nil {
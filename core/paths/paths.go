@@ -0,0 +1,59 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const appName = "goful"
+
+// ConfigDir returns the directory of the configuration, $XDG_CONFIG_HOME/goful or ~/.config/goful
+func ConfigDir() (string, error) {
+	return dir("XDG_CONFIG_HOME", ".config")
+}
+
+// StateDir returns the directory of data that outlives a run but is not worth backing up, e.g. logs,
+// $XDG_STATE_HOME/goful or ~/.local/state/goful
+func StateDir() (string, error) {
+	return dir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// ConfigFile returns the path of the configuration file
+func ConfigFile() (string, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.yaml"), nil
+}
+
+// LegacyConfigFile returns the path the configuration file was read from before ConfigFile, ~/.goful.yaml
+func LegacyConfigFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".goful.yaml"), nil
+}
+
+// LogFile returns the default path of the log
+func LogFile() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, appName+".log"), nil
+}
+
+func dir(env string, fallback string) (string, error) {
+	base := os.Getenv(env)
+	// relative paths are invalid according to the XDG Base Directory Specification and are ignored
+	if base == "" || !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, appName), nil
+}
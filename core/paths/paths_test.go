@@ -0,0 +1,59 @@
+package paths
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDirsFromEnvironment(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg/config")
+	t.Setenv("XDG_STATE_HOME", "/xdg/state")
+
+	tests := []struct {
+		name   string
+		get    func() (string, error)
+		wanted string
+	}{
+		{"config", ConfigDir, "/xdg/config/goful"},
+		{"state", StateDir, "/xdg/state/goful"},
+		{"config file", ConfigFile, "/xdg/config/goful/config.yaml"},
+		{"log file", LogFile, "/xdg/state/goful/goful.log"},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil {
+			t.Errorf("%s: did not expect error %v", tt.name, err)
+			continue
+		}
+		if got != filepath.FromSlash(tt.wanted) {
+			t.Errorf("%s: got\n%v\nwanted\n%v", tt.name, got, tt.wanted)
+		}
+	}
+}
+
+func TestDirsDefaultToHome(t *testing.T) {
+	t.Setenv("HOME", "/home/jane")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	// relative paths are ignored
+	t.Setenv("XDG_STATE_HOME", "relative/state")
+
+	tests := []struct {
+		name   string
+		get    func() (string, error)
+		wanted string
+	}{
+		{"config", ConfigDir, "/home/jane/.config/goful"},
+		{"state", StateDir, "/home/jane/.local/state/goful"},
+		{"legacy config file", LegacyConfigFile, "/home/jane/.goful.yaml"},
+	}
+	for _, tt := range tests {
+		got, err := tt.get()
+		if err != nil {
+			t.Errorf("%s: did not expect error %v", tt.name, err)
+			continue
+		}
+		if got != filepath.FromSlash(tt.wanted) {
+			t.Errorf("%s: got\n%v\nwanted\n%v", tt.name, got, tt.wanted)
+		}
+	}
+}
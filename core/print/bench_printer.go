@@ -0,0 +1,95 @@
+package print
+
+import (
+	"fmt"
+	"goful/core/bench"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxHistogramWidth = 40
+
+var (
+	benchHeadingStyle = lipgloss.NewStyle().Bold(true)
+	benchOkStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+	benchFailedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+)
+
+func SprintBench(result bench.Result) string {
+	return sprintBench(result, false)
+}
+
+func SprintPrettyBench(result bench.Result) string {
+	return sprintBench(result, true)
+}
+
+// sprintBench renders the summary of a benchmark followed by histograms of status codes and errors
+func sprintBench(result bench.Result, pretty bool) string {
+	style := func(s lipgloss.Style, str string) string {
+		if pretty {
+			return s.Render(str)
+		}
+		return str
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Requests    %d in %s\n", result.Requests, result.Elapsed.Round(time.Millisecond)))
+	sb.WriteString(fmt.Sprintf("Throughput  %.2f req/s\n", result.Throughput()))
+	errorRate := fmt.Sprintf("%.2f%% (%d)", result.ErrorRate()*100, result.Failures)
+	if result.Failures > 0 {
+		errorRate = style(benchFailedStyle, errorRate)
+	}
+	sb.WriteString(fmt.Sprintf("Errors      %s\n", errorRate))
+	sb.WriteString(fmt.Sprintf("Latency     p50 %s  p90 %s  p99 %s\n",
+		result.Percentile(50).Round(time.Microsecond),
+		result.Percentile(90).Round(time.Microsecond),
+		result.Percentile(99).Round(time.Microsecond),
+	))
+
+	largest := 0
+	for _, count := range result.StatusCodes {
+		largest = max(largest, count)
+	}
+	for _, count := range result.Errors {
+		largest = max(largest, count)
+	}
+	bar := func(count int) string {
+		return strings.Repeat("■", max(1, count*maxHistogramWidth/largest))
+	}
+
+	sb.WriteString("\n" + style(benchHeadingStyle, "Status codes") + "\n")
+	if len(result.StatusCodes) == 0 {
+		sb.WriteString("  no responses\n")
+	}
+	var codes []int
+	for code := range result.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		count := result.StatusCodes[code]
+		barStyle := benchOkStyle
+		if code >= 400 {
+			barStyle = benchFailedStyle
+		}
+		sb.WriteString(fmt.Sprintf("  %d  %6d  %s\n", code, count, style(barStyle, bar(count))))
+	}
+
+	if len(result.Errors) > 0 {
+		sb.WriteString("\n" + style(benchHeadingStyle, "Errors") + "\n")
+		var errs []string
+		for err := range result.Errors {
+			errs = append(errs, err)
+		}
+		sort.Slice(errs, func(i, j int) bool {
+			return result.Errors[errs[i]] > result.Errors[errs[j]]
+		})
+		for _, err := range errs {
+			sb.WriteString(fmt.Sprintf("  %6d  %s\n", result.Errors[err], err))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,121 @@
+package print
+
+import (
+	"fmt"
+	"goful/core/diff"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const maxDiffValueWidth = 60
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+	diffChangedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	diffHeadingStyle = lipgloss.NewStyle().Bold(true)
+)
+
+var diffMarkers = map[diff.ChangeKind]string{
+	diff.Added:   "+",
+	diff.Removed: "-",
+	diff.Changed: "~",
+}
+
+func SprintDiff(result diff.Result, labelA string, labelB string) string {
+	return sprintDiff(result, labelA, labelB, false)
+}
+
+func SprintPrettyDiff(result diff.Result, labelA string, labelB string) string {
+	return sprintDiff(result, labelA, labelB, true)
+}
+
+// sprintDiff renders the changes in side by side columns: path, value in a and value in b
+func sprintDiff(result diff.Result, labelA string, labelB string, pretty bool) string {
+	style := func(s lipgloss.Style, str string) string {
+		if pretty {
+			return s.Render(str)
+		}
+		return str
+	}
+
+	rows := [][]string{{"", "", labelA, labelB}}
+	if result.StatusA != result.StatusB {
+		rows = append(rows, []string{"~", "Status", result.StatusA, result.StatusB})
+	} else {
+		rows = append(rows, []string{" ", "Status", result.StatusA, result.StatusB})
+	}
+	for _, c := range result.Headers {
+		rows = append(rows, []string{diffMarkers[c.Kind], c.Path, c.Old, c.New})
+	}
+	headerRows := len(rows)
+	if result.BodyIsJson {
+		for _, c := range result.Body {
+			path := c.Path
+			if path == "" {
+				path = "$"
+			}
+			rows = append(rows, []string{diffMarkers[c.Kind], path, c.Old, c.New})
+		}
+	}
+
+	widths := make([]int, 4)
+	for _, row := range rows {
+		for i, col := range row {
+			widths[i] = max(widths[i], min(len(col), maxDiffValueWidth))
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		cols := make([]string, len(row))
+		for i, col := range row {
+			cols[i] = fmt.Sprintf("%-*s", widths[i], truncate(col, maxDiffValueWidth))
+		}
+		line := strings.TrimRight(fmt.Sprintf("%s %s  %s | %s", cols[0], cols[1], cols[2], cols[3]), " ")
+		switch row[0] {
+		case "+":
+			line = style(diffAddedStyle, line)
+		case "-":
+			line = style(diffRemovedStyle, line)
+		case "~":
+			line = style(diffChangedStyle, line)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	writeRow(rows[0])
+	writeRow(rows[1])
+	sb.WriteString(style(diffHeadingStyle, "Headers") + "\n")
+	if headerRows == 2 {
+		sb.WriteString("  no differences\n")
+	}
+	for _, row := range rows[2:headerRows] {
+		writeRow(row)
+	}
+
+	sb.WriteString(style(diffHeadingStyle, "Body") + "\n")
+	switch {
+	case len(result.Body) == 0:
+		sb.WriteString("  no differences\n")
+	case result.BodyIsJson:
+		for _, row := range rows[headerRows:] {
+			writeRow(row)
+		}
+	default:
+		// text bodies can not be compared structurally, show them one after another
+		change := result.Body[0]
+		sb.WriteString(style(diffRemovedStyle, fmt.Sprintf("--- %s\n%s", labelA, change.Old)) + "\n")
+		sb.WriteString(style(diffAddedStyle, fmt.Sprintf("+++ %s\n%s", labelB, change.New)) + "\n")
+	}
+	return sb.String()
+}
+
+func truncate(s string, width int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= width {
+		return s
+	}
+	return s[:width-3] + "..."
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"goful/core/model"
+	"goful/core/redact"
 	"sort"
 	"strings"
 )
@@ -13,7 +14,7 @@ func SprintHeaders(resp *model.Response) (string, error) {
 		return "", errors.New("response must not be nil")
 	}
 
-	respHeaders := resp.Headers
+	respHeaders := redact.Headers(resp.Headers)
 	respHeadersStr := ""
 	// sort header names
 	respHeaderNames := sortHeaderNames(resp.Headers)
@@ -6,6 +6,7 @@ import (
 	"github.com/alecthomas/chroma/v2"
 	"github.com/alecthomas/chroma/v2/lexers"
 	"goful/core/model"
+	"goful/core/redact"
 )
 
 func SprintFullResponse(resp *model.Response) (string, error) {
@@ -50,6 +51,9 @@ func sprintResponse(resp *model.Response, pretty bool, printHeaders bool, printB
 		respStr += respBodyStr
 	}
 
+	// a server echoing the request back must not reveal credentials either
+	respStr = redact.String(respStr)
+
 	if pretty {
 		buf := new(bytes.Buffer)
 		// FIXME tokenise different parts separately? Now body content-type dictates whole response formatting style
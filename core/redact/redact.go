@@ -0,0 +1,235 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"goful/core/model"
+	"goful/core/secrets"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+// Mask replaces redacted values, the same as resolved secrets are masked with
+const Mask = secrets.Mask
+
+// DefaultHeaders carry credentials in most APIs
+var DefaultHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"}
+
+// DefaultVariables match the names of profile variables that usually hold credentials
+var DefaultVariables = []string{"*token*", "*secret*", "*password*", "*passwd*", "*apikey*", "*api_key*", "*api-key*"}
+
+// DefaultPatterns match credentials that are recognizable wherever they appear
+var DefaultPatterns = []string{`(?i)bearer\s+([A-Za-z0-9._~+/=-]+)`}
+
+// Rules tell what to redact from output, history, exports and logs
+type Rules struct {
+	// Headers whose values are redacted, compared case-insensitively
+	Headers []string
+	// Variables are names of profile variables whose values are redacted wherever they appear.
+	// Names are compared case-insensitively and may use path.Match wildcards, e.g. "*token*".
+	Variables []string
+	// Patterns match text to redact. When a pattern has groups only the groups are redacted.
+	Patterns []*regexp.Regexp
+}
+
+var (
+	mu     sync.RWMutex
+	active = DefaultRules()
+	values []string
+)
+
+// DefaultRules returns the rules used until Configure is called
+func DefaultRules() Rules {
+	rules, _ := NewRules(DefaultHeaders, DefaultVariables, DefaultPatterns)
+	return rules
+}
+
+// NewRules compiles the patterns into rules
+func NewRules(headers []string, variables []string, patterns []string) (Rules, error) {
+	rules := Rules{Headers: headers, Variables: variables}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid pattern %s: %w", p, err)
+		}
+		rules.Patterns = append(rules.Patterns, re)
+	}
+	return rules, nil
+}
+
+// ConfiguredRules reads the rules from redact.headers, redact.variables and redact.patterns.
+func ConfiguredRules() (Rules, error) {
+	return NewRules(
+		viper.GetStringSlice("redact.headers"),
+		viper.GetStringSlice("redact.variables"),
+		viper.GetStringSlice("redact.patterns"),
+	)
+}
+
+// Configure replaces the active rules
+func Configure(rules Rules) {
+	mu.Lock()
+	defer mu.Unlock()
+	active = rules
+}
+
+// SetVariables redacts the values of the variables of the profiles in use whose names match the rules. The values
+// of the profiles set before are no longer redacted, so it is called whenever the profiles in use change.
+func SetVariables(profiles ...map[string]string) {
+	mu.Lock()
+	defer mu.Unlock()
+	values = nil
+	for _, variables := range profiles {
+		for k, v := range variables {
+			if len(v) < secrets.MinMaskedLength || secrets.IsRef(v) || !matchesAny(active.Variables, k) {
+				continue
+			}
+			for _, variant := range secrets.Variants(v) {
+				if !slices.Contains(values, variant) {
+					values = append(values, variant)
+				}
+			}
+		}
+	}
+}
+
+// IsSensitiveVariable tells whether the value of variable name is redacted
+func IsSensitiveVariable(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return matchesAny(active.Variables, name)
+}
+
+// IsSensitiveHeader tells whether the values of header name are redacted
+func IsSensitiveHeader(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, h := range active.Headers {
+		if strings.EqualFold(h, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// String redacts resolved secrets, values of sensitive variables and matches of the patterns in s
+func String(s string) string {
+	return string(Bytes([]byte(s)))
+}
+
+// Bytes redacts resolved secrets, values of sensitive variables and matches of the patterns in b
+func Bytes(b []byte) []byte {
+	b = secrets.MaskBytes(b)
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, v := range values {
+		b = bytes.ReplaceAll(b, []byte(v), []byte(Mask))
+	}
+	for _, re := range active.Patterns {
+		b = redactPattern(re, b)
+	}
+	return b
+}
+
+// Headers returns a copy of headers where the values of sensitive headers are masked and the other values redacted
+func Headers(headers model.Headers) model.Headers {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(model.Headers, len(headers))
+	for name, headerValues := range headers {
+		redactedValues := make(model.HeaderValues, len(headerValues))
+		for i, v := range headerValues {
+			if IsSensitiveHeader(name) {
+				redactedValues[i] = Mask
+			} else {
+				redactedValues[i] = String(v)
+			}
+		}
+		redacted[name] = redactedValues
+	}
+	return redacted
+}
+
+// Request returns a copy of request with its url, headers and body redacted
+func Request(request model.Request) model.Request {
+	request.Url = String(request.Url)
+	request.Headers = Headers(request.Headers)
+	switch body := request.Body.(type) {
+	case string:
+		request.Body = String(body)
+	case []byte:
+		request.Body = Bytes(body)
+	}
+	return request
+}
+
+// Response returns a copy of response with its headers and body redacted
+func Response(response model.Response) model.Response {
+	response.Headers = Headers(response.Headers)
+	response.Body = Bytes(response.Body)
+	return response
+}
+
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter redacts everything written to w, e.g. logs
+func NewWriter(w io.Writer) io.Writer {
+	return writer{w: w}
+}
+
+func (r writer) Write(p []byte) (int, error) {
+	_, err := r.w.Write(Bytes(p))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func redactPattern(re *regexp.Regexp, b []byte) []byte {
+	matches := re.FindAllSubmatchIndex(b, -1)
+	if matches == nil {
+		return b
+	}
+	var buf bytes.Buffer
+	last := 0
+	for _, match := range matches {
+		spans := [][2]int{{match[0], match[1]}}
+		if len(match) > 2 {
+			spans = nil
+			for i := 2; i+1 < len(match); i += 2 {
+				if match[i] >= 0 {
+					spans = append(spans, [2]int{match[i], match[i+1]})
+				}
+			}
+		}
+		for _, span := range spans {
+			if span[0] < last {
+				continue
+			}
+			buf.Write(b[last:span[0]])
+			buf.WriteString(Mask)
+			last = span[1]
+		}
+	}
+	buf.Write(b[last:])
+	return buf.Bytes()
+}
+
+func matchesAny(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
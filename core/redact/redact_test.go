@@ -0,0 +1,149 @@
+package redact
+
+import (
+	"bytes"
+	"goful/core/model"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func configure(t *testing.T, headers []string, variables []string, patterns []string) {
+	rules, err := NewRules(headers, variables, patterns)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	Configure(rules)
+	t.Cleanup(func() {
+		Configure(DefaultRules())
+		mu.Lock()
+		values = nil
+		mu.Unlock()
+	})
+}
+
+func TestNewRulesWithInvalidPattern(t *testing.T) {
+	_, err := NewRules(nil, nil, []string{"("})
+	if err == nil {
+		t.Errorf("expected error for an invalid pattern")
+	}
+}
+
+func TestStringRedactsVariables(t *testing.T) {
+	configure(t, DefaultHeaders, []string{"*token*", "Password"}, nil)
+	SetVariables(map[string]string{
+		"api_token": "abcdef123456",
+		"PASSWORD":  "hunter2hunter2",
+		"short":     "abc",
+		"TOKEN2":    "abc",
+		"domain":    "foobar.com",
+		"ref_token": "cmd://pass show token",
+	})
+
+	got := String("token abcdef123456 password hunter2hunter2 at foobar.com abc")
+	wanted := "token **** password **** at foobar.com abc"
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+	if !IsSensitiveVariable("API_TOKEN") || IsSensitiveVariable("domain") {
+		t.Errorf("expected only variables matching the rules to be sensitive")
+	}
+}
+
+func TestStringRedactsEncodedVariables(t *testing.T) {
+	configure(t, DefaultHeaders, []string{"*token*"}, nil)
+	SetVariables(map[string]string{"api_token": "ab+cd/ef=="})
+
+	got := String(`GET /users?key=ab%2Bcd%2Fef%3D%3D {"key":"ab+cd/ef=="}`)
+	wanted := `GET /users?key=**** {"key":"****"}`
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+}
+
+func TestSetVariablesReplaces(t *testing.T) {
+	configure(t, DefaultHeaders, []string{"*token*"}, nil)
+	SetVariables(map[string]string{"api_token": "staging-token"}, map[string]string{"api_token": "qa-token-123"})
+	SetVariables(map[string]string{"api_token": "prod-token-456"})
+
+	got := String("staging-token qa-token-123 prod-token-456")
+	wanted := "staging-token qa-token-123 ****"
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+}
+
+func TestStringRedactsPatterns(t *testing.T) {
+	configure(t, DefaultHeaders, nil, []string{`(?i)bearer\s+([A-Za-z0-9._~+/=-]+)`, `ghp_[A-Za-z0-9]+`})
+
+	got := String(`{"auth":"Bearer eyJhbGciOi.J9","github":"ghp_abc123XYZ"}`)
+	wanted := `{"auth":"Bearer ****","github":"****"}`
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	configure(t, []string{"authorization", "X-Api-Key"}, nil, []string{`sk_live_[a-z0-9]+`})
+
+	headers := model.Headers{
+		"Authorization": {"Basic amFuZTpzZWNyZXQ="},
+		"X-API-KEY":     {"key1", "key2"},
+		"X-Forwarded":   {"sk_live_abc123"},
+		"Accept":        {"application/json"},
+	}
+	wanted := model.Headers{
+		"Authorization": {"****"},
+		"X-API-KEY":     {"****", "****"},
+		"X-Forwarded":   {"****"},
+		"Accept":        {"application/json"},
+	}
+	got := Headers(headers)
+	if !cmp.Equal(got, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+	if headers["Authorization"][0] != "Basic amFuZTpzZWNyZXQ=" {
+		t.Errorf("expected headers not to be modified, got %v", headers)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	configure(t, DefaultHeaders, DefaultVariables, DefaultPatterns)
+	SetVariables(map[string]string{"client_secret": "s3cr3t-value"})
+
+	request := model.Request{
+		Url:     "https://foobar.com/token?client_secret=s3cr3t-value",
+		Method:  "POST",
+		Headers: model.Headers{"Authorization": {"Bearer abc.def"}},
+		Body:    `{"client_secret":"s3cr3t-value"}`,
+	}
+	wanted := model.Request{
+		Url:     "https://foobar.com/token?client_secret=****",
+		Method:  "POST",
+		Headers: model.Headers{"Authorization": {"****"}},
+		Body:    `{"client_secret":"****"}`,
+	}
+	got := Request(request)
+	if !cmp.Equal(got, wanted) {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	configure(t, DefaultHeaders, DefaultVariables, DefaultPatterns)
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	line := []byte(`{"message":"Authorization: Bearer abc.def"}` + "\n")
+	n, err := w.Write(line)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if n != len(line) {
+		t.Errorf("expected %d bytes written, got %d", len(line), n)
+	}
+	wanted := `{"message":"Authorization: Bearer ****"}` + "\n"
+	if buf.String() != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", buf.String(), wanted)
+	}
+}
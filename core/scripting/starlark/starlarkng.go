@@ -1,6 +1,7 @@
 package starlarkng
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"goful/core/model"
@@ -12,9 +13,11 @@ import (
 	"go.starlark.net/syntax"
 )
 
-func RunStarlarkScript(request model.RequestMold, previousResponse model.Response, profile model.Profile) (map[string]interface{}, error) {
+// RunStarlarkScript executes the script of the request and returns its globals as Go values.
+// Cancelling ctx interrupts a running script.
+func RunStarlarkScript(ctx context.Context, request model.RequestMold, previousResponse model.Response, profile model.Profile) (map[string]interface{}, error) {
 
-	log.Info().Msgf("Running Starlark script with request %v, previousResponse %v, profile %v", request, previousResponse, profile)
+	log.Debug().Msgf("Running Starlark script of request %s with profile %s", request.Name(), profile.Name)
 
 	if request.Starlark == nil {
 		log.Error().Msg("Starlark request is nil, aborting")
@@ -38,6 +41,15 @@ func RunStarlarkScript(request model.RequestMold, previousResponse model.Respons
 	}
 
 	thread := &starlark.Thread{Name: "starlark runner thread"}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
 
 	// TODO read from config
 	fileOptions := syntax.FileOptions{
@@ -52,12 +64,16 @@ func RunStarlarkScript(request model.RequestMold, previousResponse model.Respons
 	starlarkRequest := request.Starlark
 
 	globals, err := starlark.ExecFileOptions(&fileOptions, thread, request.Name(), starlarkRequest.Script, predeclared)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 	if err != nil {
 		fmt.Print(err)
 		return nil, err
 	}
 
-	log.Debug().Msgf("Run Starlark script and got result %v", globals)
+	// values are left out, they may hold credentials of the profile
+	log.Debug().Msgf("Run Starlark script and got globals %v", globals.Keys())
 
 	values := make(map[string]interface{})
 	for _, name := range globals.Keys() {
@@ -69,7 +85,5 @@ func RunStarlarkScript(request model.RequestMold, previousResponse model.Respons
 		values[name] = goValue
 	}
 
-	log.Debug().Msgf("Starlark result converted to Golang values %v", values)
-
 	return values, nil
 }
@@ -0,0 +1,169 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	keyringPrefix = "secret://keyring/"
+	cmdPrefix     = "cmd://"
+	filePrefix    = "file://"
+	// keyringService holds the secrets referenced without a service, e.g. secret://keyring/github
+	keyringService = "goful"
+	Mask           = "****"
+	// MinMaskedLength is the length of the shortest masked value, masking shorter ones would garble the output
+	MinMaskedLength = 4
+)
+
+var (
+	mu       sync.RWMutex
+	resolved = make(map[string]string)
+	masked   []string
+)
+
+// IsRef tells whether value refers to a secret instead of being the value itself
+func IsRef(value string) bool {
+	return strings.HasPrefix(value, keyringPrefix) || strings.HasPrefix(value, cmdPrefix) || strings.HasPrefix(value, filePrefix)
+}
+
+// Resolve returns the secret ref refers to, values that are not references are returned as is.
+// References are resolved once per process and the secrets are masked from then on, see MaskString.
+//
+//   - secret://keyring/<name> reads name of service goful from the system keyring,
+//     secret://keyring/<service>/<user> any other item
+//   - cmd://<command> runs the command with the shell and reads its output, e.g. cmd://pass show api/token
+//   - file://<path> reads a file, ~ is expanded to the home directory
+//
+// Trailing newlines are removed from secrets read from commands and files.
+func Resolve(ctx context.Context, ref string) (string, error) {
+	if !IsRef(ref) {
+		return ref, nil
+	}
+	mu.RLock()
+	secret, ok := resolved[ref]
+	mu.RUnlock()
+	if ok {
+		return secret, nil
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(ref, keyringPrefix):
+		secret, err = readKeyring(strings.TrimPrefix(ref, keyringPrefix))
+	case strings.HasPrefix(ref, cmdPrefix):
+		secret, err = runCommand(ctx, strings.TrimPrefix(ref, cmdPrefix))
+	case strings.HasPrefix(ref, filePrefix):
+		secret, err = readFile(strings.TrimPrefix(ref, filePrefix))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	resolved[ref] = secret
+	addMasked(secret)
+	return secret, nil
+}
+
+func addMasked(secret string) {
+	if len(secret) < MinMaskedLength {
+		return
+	}
+	for _, v := range Variants(secret) {
+		if !slices.Contains(masked, v) {
+			masked = append(masked, v)
+		}
+	}
+}
+
+// Variants returns value as it is and the forms it takes when escaped in JSON, e.g. in the history,
+// and when url encoded in a query
+func Variants(value string) []string {
+	variants := []string{value}
+	if encoded, err := json.Marshal(value); err == nil {
+		if escaped := string(encoded[1 : len(encoded)-1]); escaped != value {
+			variants = append(variants, escaped)
+		}
+	}
+	if escaped := url.QueryEscape(value); !slices.Contains(variants, escaped) {
+		variants = append(variants, escaped)
+	}
+	return variants
+}
+
+// MaskString replaces the resolved secrets in s
+func MaskString(s string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, secret := range masked {
+		s = strings.ReplaceAll(s, secret, Mask)
+	}
+	return s
+}
+
+// MaskBytes replaces the resolved secrets in b
+func MaskBytes(b []byte) []byte {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, secret := range masked {
+		b = bytes.ReplaceAll(b, []byte(secret), []byte(Mask))
+	}
+	return b
+}
+
+func readKeyring(path string) (string, error) {
+	service, user, found := strings.Cut(path, "/")
+	if !found {
+		service, user = keyringService, path
+	}
+	secret, err := keyring.Get(service, user)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s of service %s from keyring: %w", user, service, err)
+	}
+	return secret, nil
+}
+
+func runCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("command %s failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+func readFile(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,159 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestIsRef(t *testing.T) {
+	tests := map[string]bool{
+		"secret://keyring/github": true,
+		"cmd://pass show api":     true,
+		"file:///tmp/token":       true,
+		"plain value":             false,
+		"https://foobar.com":      false,
+		"":                        false,
+	}
+	for value, wanted := range tests {
+		if got := IsRef(value); got != wanted {
+			t.Errorf("IsRef(%q): got %v wanted %v", value, got, wanted)
+		}
+	}
+}
+
+func TestResolveNonRef(t *testing.T) {
+	got, err := Resolve(context.Background(), "plain value")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if got != "plain value" {
+		t.Errorf("got\n%v\nwanted\n%v", got, "plain value")
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	err := os.WriteFile(path, []byte("file-secret-value\n"), 0600)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+
+	got, err := Resolve(context.Background(), "file://"+path)
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if got != "file-secret-value" {
+		t.Errorf("got\n%v\nwanted\n%v", got, "file-secret-value")
+	}
+}
+
+func TestResolveMissingFile(t *testing.T) {
+	_, err := Resolve(context.Background(), "file://"+filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Errorf("expected error for a missing file")
+	}
+}
+
+func TestResolveCommand(t *testing.T) {
+	got, err := Resolve(context.Background(), "cmd://echo command-secret-value")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if got != "command-secret-value" {
+		t.Errorf("got\n%v\nwanted\n%v", got, "command-secret-value")
+	}
+}
+
+func TestResolveFailingCommand(t *testing.T) {
+	_, err := Resolve(context.Background(), "cmd://echo oops >&2; exit 3")
+	if err == nil {
+		t.Errorf("expected error for a failing command")
+	}
+}
+
+func TestResolveCaches(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "counter")
+	ref := "cmd://echo x >> " + counter + "; echo cached-secret-value"
+	for i := 0; i < 2; i++ {
+		_, err := Resolve(context.Background(), ref)
+		if err != nil {
+			t.Errorf("did not expect error %v", err)
+		}
+	}
+	b, err := os.ReadFile(counter)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	if string(b) != "x\n" {
+		t.Errorf("expected the command to run once, got %q", string(b))
+	}
+}
+
+func TestResolveKeyring(t *testing.T) {
+	keyring.MockInit()
+	err := keyring.Set(keyringService, "github", "keyring-secret-value")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	err = keyring.Set("other", "me", "other-keyring-secret")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+
+	got, err := Resolve(context.Background(), "secret://keyring/github")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if got != "keyring-secret-value" {
+		t.Errorf("got\n%v\nwanted\n%v", got, "keyring-secret-value")
+	}
+
+	got, err = Resolve(context.Background(), "secret://keyring/other/me")
+	if err != nil {
+		t.Errorf("did not expect error %v", err)
+	}
+	if got != "other-keyring-secret" {
+		t.Errorf("got\n%v\nwanted\n%v", got, "other-keyring-secret")
+	}
+
+	_, err = Resolve(context.Background(), "secret://keyring/missing")
+	if err == nil {
+		t.Errorf("expected error for a missing keyring item")
+	}
+}
+
+func TestMask(t *testing.T) {
+	_, err := Resolve(context.Background(), `cmd://printf '%s' 'mask"me'`)
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	_, err = Resolve(context.Background(), "cmd://echo abc")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+
+	got := MaskString(`token mask"me in {"token":"mask\"me"} abc`)
+	wanted := `token **** in {"token":"****"} abc`
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+
+	_, err = Resolve(context.Background(), "cmd://printf '%s' 'ab+cd/ef=='")
+	if err != nil {
+		t.Fatalf("did not expect error %v", err)
+	}
+	got = MaskString("/users?key=ab%2Bcd%2Fef%3D%3D")
+	wanted = "/users?key=****"
+	if got != wanted {
+		t.Errorf("got\n%v\nwanted\n%v", got, wanted)
+	}
+
+	gotBytes := MaskBytes([]byte(`mask"me`))
+	if string(gotBytes) != Mask {
+		t.Errorf("got\n%v\nwanted\n%v", string(gotBytes), Mask)
+	}
+}
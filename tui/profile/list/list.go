@@ -2,7 +2,7 @@ package listtui
 
 import (
 	"fmt"
-	"os"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -13,12 +13,20 @@ import (
 var listStyle = lipgloss.NewStyle().BorderBackground(lipgloss.Color("#cdd6f4"))
 
 type Profile struct {
-	Name      string
-	Variables int
+	Name     string
+	Filename string
+	Keys     []string
+	// Encrypted profiles are edited with goful profiles edit, not in the TUI
+	Encrypted bool
 }
 
-func (i Profile) Title() string       { return i.Name }
-func (i Profile) Description() string { return fmt.Sprintf("Vars: %d", i.Variables) }
+func (i Profile) Title() string { return i.Name }
+func (i Profile) Description() string {
+	if len(i.Keys) == 0 {
+		return fmt.Sprintf("%s · no variables", i.Filename)
+	}
+	return fmt.Sprintf("%s · %s", i.Filename, strings.Join(i.Keys, ", "))
+}
 func (i Profile) FilterValue() string { return i.Name }
 
 type Model struct {
@@ -38,12 +46,16 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEnter:
+			// enter applies the filter while it is being typed
+			if m.List.FilterState() == list.Filtering {
+				break
+			}
 			i, ok := m.List.SelectedItem().(Profile)
 			if ok {
 				m.Selection = i
 				m.Selected = true
 			}
-			return m, tea.Cmd(func() tea.Msg { return ProfileSelectedMsg{} })
+			return m, tea.Cmd(func() tea.Msg { return ProfileSelectedMsg{Profile: i} })
 		}
 	case tea.WindowSizeMsg:
 		h, v := listStyle.GetFrameSize()
@@ -90,16 +102,9 @@ func New(profiles []Profile, width, height int, additionalFullHelpKeys []key.Bin
 }
 
 func (m Model) View() string {
-
-	f, err := tea.LogToFile("debug.log", "debug")
-	if err != nil {
-		fmt.Println("fatal:", err)
-		os.Exit(1)
-	}
-	f.Write([]byte(fmt.Sprintf("w:%v, h: %v\n", m.width, m.height)))
-
-	defer f.Close()
 	return m.List.View()
 }
 
-type ProfileSelectedMsg struct{}
+type ProfileSelectedMsg struct {
+	Profile Profile
+}
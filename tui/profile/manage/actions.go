@@ -0,0 +1,129 @@
+package managetui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"goful/core/envfile"
+	"goful/core/loader"
+	list "goful/tui/profile/list"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog/log"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
+
+func profilePath(root string, name string) string {
+	return filepath.Join(root, loader.ProfileFilename(name))
+}
+
+func encryptedProfilePath(root string, name string) string {
+	return filepath.Join(root, loader.EncryptedProfileFilename(name))
+}
+
+func loadProfiles(root string) ([]list.Profile, error) {
+	loadedProfiles, err := loader.ReadProfiles(root)
+	if err != nil {
+		return nil, err
+	}
+	var profiles []list.Profile
+	for _, p := range loadedProfiles {
+		var keys []string
+		for k := range p.Variables {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		profile := list.Profile{
+			Name:     p.Name,
+			Filename: loader.ProfileFilename(p.Name),
+			Keys:     keys,
+		}
+		if _, err := os.Stat(encryptedProfilePath(root, p.Name)); err == nil {
+			profile.Filename = loader.EncryptedProfileFilename(p.Name)
+			profile.Encrypted = true
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// createProfile writes a new profile file, existing files are never overwritten
+func createProfile(root string, name string, content []byte) error {
+	if err := loader.ValidateProfileName(name); err != nil {
+		return err
+	}
+	path := profilePath(root, name)
+	if _, err := os.Stat(encryptedProfilePath(root, name)); err == nil {
+		return fmt.Errorf("profile %s already exists", name)
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("profile %s already exists", name)
+		}
+		return err
+	}
+	defer file.Close()
+	log.Info().Msgf("Creating profile file %s", path)
+	_, err = file.Write(content)
+	if err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+func cloneProfile(root string, source string, name string) error {
+	content, err := os.ReadFile(profilePath(root, source))
+	if err != nil {
+		return err
+	}
+	return createProfile(root, name, content)
+}
+
+func deleteProfile(root string, name string) error {
+	path := profilePath(root, name)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		path = encryptedProfilePath(root, name)
+	}
+	log.Info().Msgf("Deleting profile file %s", path)
+	return os.Remove(path)
+}
+
+// readProfileFile returns the editable file of profile name and the values of its variables as godotenv reads them
+func readProfileFile(root string, name string) (*envfile.File, map[string]string, error) {
+	content, err := os.ReadFile(profilePath(root, name))
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := godotenv.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, nil, err
+	}
+	return envfile.Parse(content), values, nil
+}
+
+func writeProfileFile(root string, name string, file *envfile.File) error {
+	path := profilePath(root, name)
+	log.Debug().Msgf("Writing profile file %s", path)
+	return os.WriteFile(path, file.Bytes(), 0644)
+}
+
+// validateProfileName checks the name while it is typed, the complete name is checked with loader.ValidateProfileName
+func validateProfileName(s string) error {
+	if !profileNamePattern.MatchString(s) {
+		return errors.New("Use letters, digits, - and _ only.")
+	}
+	return nil
+}
+
+func validateVariableKey(s string) error {
+	if s != "" && !envfile.IsValidKey(s) {
+		return errors.New("Use letters, digits, _, . and - only, starting with a letter or _.")
+	}
+	return nil
+}
@@ -2,15 +2,19 @@ package managetui
 
 import (
 	"fmt"
-	"goful/core/model"
+	"goful/core/envfile"
+	"goful/core/loader"
 	list "goful/tui/profile/list"
 	create "goful/tui/request/prompt"
 	"os"
+	"strings"
 
-	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	bubblelist "github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/rs/zerolog/log"
 )
 
 type ActiveView int
@@ -21,21 +25,64 @@ const (
 	Update
 )
 
+const (
+	CreateProfile  = "CrProf"
+	CloneProfile   = "ClProf"
+	AddVariable    = "AddVar"
+	SetVariable    = "SetVar"
+	RenameVariable = "RnVar"
+)
+
+const (
+	CreateProfileLabel  = "Choose a name for your profile. It is saved to .env.<name> in the workspace."
+	CloneProfileLabel   = "Choose a name for the copy of %s."
+	AddVariableLabel    = "Choose a name for the variable."
+	SetVariableLabel    = "Value of %s. Use {var} in requests to refer to it."
+	RenameVariableLabel = "Rename variable %s."
+	valueCharLimit      = 1024
+)
+
+var (
+	titleStyle  = lipgloss.NewStyle().Background(lipgloss.Color("#cba6f7")).Foreground(lipgloss.Color("#1e1e2e")).Padding(0, 1)
+	helpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+)
+
 var keys = []key.Binding{
+	key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "edit variables"),
+	),
 	key.NewBinding(
 		key.WithKeys("a"),
-		key.WithHelp("a", "Add new profile"),
-	)}
+		key.WithHelp("a", "add profile"),
+	),
+	key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "clone profile"),
+	),
+	key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "delete profile"),
+	),
+}
 
 type uiModel struct {
-	list     list.Model
-	create   create.Model
-	active   ActiveView
+	root   string
+	list   list.Model
+	create create.Model
+	table  table.Model
+	active ActiveView
+	// view to return to when the prompt is answered or cancelled
+	promptParent ActiveView
+	// profile whose variables are edited
 	selected list.Profile
-	width    int
-	height   int
-	debug    string
-	help     help.Model
+	file     *envfile.File
+	// profile waiting for the deletion to be confirmed
+	confirmDelete string
+	status        string
+	width         int
+	height        int
 }
 
 func (m uiModel) Init() tea.Cmd {
@@ -47,20 +94,32 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.list.List.SetSize(msg.Width, msg.Height-1)
+		layoutTable(&m)
+		var cmd tea.Cmd
+		m.create, cmd = m.create.Update(msg)
+		return m, cmd
 	case tea.KeyMsg:
-		switch keypress := msg.String(); keypress {
-		case "ctrl+c":
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
-		case "a":
-			if m.active != Create {
-				m.active = Create
-				return m, nil
+		}
+		switch m.active {
+		case List:
+			if m.list.List.FilterState() == bubblelist.Filtering {
+				break
 			}
+			return updateList(m, msg)
+		case Update:
+			return updateTable(m, msg)
 		}
 	case list.ProfileSelectedMsg:
-		return m, tea.Quit
+		return openProfile(m, msg.Profile)
+	case create.PromptCancelledMsg:
+		m.active = m.promptParent
+		return m, nil
 	case create.PromptAnsweredMsg:
-		return m, tea.Quit
+		m.active = m.promptParent
+		return answerPrompt(m, msg)
 	}
 
 	var cmd tea.Cmd
@@ -69,28 +128,291 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list, cmd = m.list.Update(msg)
 	case Create:
 		m.create, cmd = m.create.Update(msg)
+	case Update:
+		m.table, cmd = m.table.Update(msg)
+	}
+	return m, cmd
+}
+
+func updateList(m uiModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	selected, hasSelection := m.list.List.SelectedItem().(list.Profile)
+	if m.confirmDelete != "" {
+		name := m.confirmDelete
+		m.confirmDelete = ""
+		m.status = ""
+		if msg.String() == "y" {
+			err := deleteProfile(m.root, name)
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to delete profile %s", name)
+				m.status = fmt.Sprintf("Failed to delete %s: %v", name, err)
+				return m, nil
+			}
+			m.status = fmt.Sprintf("Deleted profile %s", name)
+			return m, reloadProfiles(&m)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "a":
+		return openPrompt(m, create.PromptContext{Key: CreateProfile}, "", CreateProfileLabel, validateProfileName), nil
+	case "c":
+		if hasSelection && selected.Encrypted {
+			m.status = fmt.Sprintf("Profile %s is encrypted and can not be cloned", selected.Name)
+			return m, nil
+		}
+		if hasSelection {
+			context := create.PromptContext{Key: CloneProfile, Additional: selected.Name}
+			return openPrompt(m, context, selected.Name+"-copy", fmt.Sprintf(CloneProfileLabel, selected.Name), validateProfileName), nil
+		}
+	case "x":
+		if hasSelection {
+			m.confirmDelete = selected.Name
+			m.status = fmt.Sprintf("Delete profile %s and its file %s? y to confirm", selected.Name, selected.Filename)
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func updateTable(m uiModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var selectedKey string
+	if row := m.table.SelectedRow(); row != nil {
+		selectedKey = row[0]
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.active = List
+		m.status = ""
+		return m, reloadProfiles(&m)
+	case "a":
+		m.status = ""
+		return openPrompt(m, create.PromptContext{Key: AddVariable}, "", AddVariableLabel, validateVariableKey), nil
+	case "enter", "e":
+		if selectedKey != "" {
+			m.status = ""
+			m = openPrompt(m, create.PromptContext{Key: SetVariable, Additional: selectedKey}, m.table.SelectedRow()[1], fmt.Sprintf(SetVariableLabel, selectedKey), nil)
+			m.create.Heading = "Value"
+			m.create.SetCharLimit(valueCharLimit)
+			return m, nil
+		}
+	case "r":
+		if selectedKey != "" {
+			m.status = ""
+			context := create.PromptContext{Key: RenameVariable, Additional: selectedKey}
+			return openPrompt(m, context, selectedKey, fmt.Sprintf(RenameVariableLabel, selectedKey), validateVariableKey), nil
+		}
+	case "x":
+		if selectedKey != "" {
+			m.file.Delete(selectedKey)
+			return saveProfile(m, fmt.Sprintf("Deleted %s", selectedKey))
+		}
 	}
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
 	return m, cmd
 }
 
+func answerPrompt(m uiModel, msg create.PromptAnsweredMsg) (tea.Model, tea.Cmd) {
+	input := strings.TrimSpace(msg.Input)
+	switch msg.Context.Key {
+	case CreateProfile, CloneProfile:
+		if err := loader.ValidateProfileName(input); err != nil {
+			m.status = fmt.Sprintf("Can not create profile %s: %v", input, err)
+			return m, nil
+		}
+		var err error
+		if msg.Context.Key == CreateProfile {
+			err = createProfile(m.root, input, []byte(fmt.Sprintf("# Variables of profile %s\n", input)))
+		} else {
+			err = cloneProfile(m.root, msg.Context.Additional.(string), input)
+		}
+		if err != nil {
+			log.Error().Err(err).Msgf("Failed to create profile %s", input)
+			m.status = fmt.Sprintf("Failed to create %s: %v", input, err)
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Created profile %s", input)
+		// the new profile may not match the filter, which is cleared so that the profile is visible and its index
+		// among the items is the one to select
+		m.list.List.ResetFilter()
+		cmd := reloadProfiles(&m)
+		for i, item := range m.list.List.Items() {
+			if p, ok := item.(list.Profile); ok && p.Name == input {
+				m.list.List.Select(i)
+			}
+		}
+		return m, cmd
+	case AddVariable:
+		if input == "" {
+			return m, nil
+		}
+		if hasVariable(m, input) {
+			m.status = fmt.Sprintf("Variable %s already exists", input)
+			return m, nil
+		}
+		m.file.Set(input, "")
+		saved, _ := saveProfile(m, fmt.Sprintf("Added %s", input))
+		model := saved.(uiModel)
+		selectVariable(&model, input)
+		// continue with the value of the new variable
+		model = openPrompt(model, create.PromptContext{Key: SetVariable, Additional: input}, "", fmt.Sprintf(SetVariableLabel, input), nil)
+		model.create.Heading = "Value"
+		model.create.SetCharLimit(valueCharLimit)
+		return model, nil
+	case SetVariable:
+		variable := msg.Context.Additional.(string)
+		m.file.Set(variable, msg.Input)
+		return saveProfile(m, fmt.Sprintf("Saved %s", variable))
+	case RenameVariable:
+		variable := msg.Context.Additional.(string)
+		if input == "" || input == variable {
+			return m, nil
+		}
+		if hasVariable(m, input) {
+			m.status = fmt.Sprintf("Variable %s already exists", input)
+			return m, nil
+		}
+		m.file.Rename(variable, input)
+		saved, cmd := saveProfile(m, fmt.Sprintf("Renamed %s to %s", variable, input))
+		model := saved.(uiModel)
+		selectVariable(&model, input)
+		return model, cmd
+	}
+	return m, nil
+}
+
+func openPrompt(m uiModel, context create.PromptContext, initialValue string, label string, validator func(s string) error) uiModel {
+	m.promptParent = m.active
+	m.active = Create
+	m.create = create.New(context, initialValue, label, validator, m.width)
+	return m
+}
+
+func openProfile(m uiModel, profile list.Profile) (tea.Model, tea.Cmd) {
+	if profile.Encrypted {
+		m.status = fmt.Sprintf("Profile %s is encrypted, edit it with goful profiles edit %s", profile.Name, profile.Name)
+		return m, nil
+	}
+	file, values, err := readProfileFile(m.root, profile.Name)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read profile %s", profile.Name)
+		m.status = fmt.Sprintf("Failed to read %s: %v", profile.Filename, err)
+		return m, nil
+	}
+	m.selected = profile
+	m.file = file
+	m.active = Update
+	m.status = ""
+	m.table = newVariableTable(file, values)
+	layoutTable(&m)
+	return m, nil
+}
+
+// saveProfile writes the edited file and reads it back so that the table shows the values as they are loaded
+func saveProfile(m uiModel, status string) (tea.Model, tea.Cmd) {
+	err := writeProfileFile(m.root, m.selected.Name, m.file)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to save profile %s", m.selected.Name)
+		m.status = fmt.Sprintf("Failed to save %s: %v", m.selected.Filename, err)
+		return m, nil
+	}
+	file, values, err := readProfileFile(m.root, m.selected.Name)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read profile %s", m.selected.Name)
+		m.status = fmt.Sprintf("Failed to read %s: %v", m.selected.Filename, err)
+		return m, nil
+	}
+	m.file = file
+	cursor := m.table.Cursor()
+	m.table.SetRows(variableRows(file, values))
+	m.table.SetCursor(min(cursor, max(0, len(m.table.Rows())-1)))
+	m.status = status
+	return m, nil
+}
+
+func reloadProfiles(m *uiModel) tea.Cmd {
+	profiles, err := loadProfiles(m.root)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read profiles")
+		m.status = fmt.Sprintf("Failed to read profiles: %v", err)
+		return nil
+	}
+	var items []bubblelist.Item
+	for _, p := range profiles {
+		items = append(items, p)
+	}
+	return m.list.List.SetItems(items)
+}
+
+func hasVariable(m uiModel, name string) bool {
+	for _, k := range m.file.Keys() {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+func selectVariable(m *uiModel, name string) {
+	for i, row := range m.table.Rows() {
+		if row[0] == name {
+			m.table.SetCursor(i)
+		}
+	}
+}
+
+func variableRows(file *envfile.File, values map[string]string) []table.Row {
+	var rows []table.Row
+	for _, k := range file.Keys() {
+		rows = append(rows, table.Row{k, values[k]})
+	}
+	return rows
+}
+
+func newVariableTable(file *envfile.File, values map[string]string) table.Model {
+	styles := table.DefaultStyles()
+	styles.Header = styles.Header.BorderStyle(lipgloss.NormalBorder()).BorderBottom(true).Bold(true)
+	styles.Selected = styles.Selected.Foreground(lipgloss.Color("#1e1e2e")).Background(lipgloss.Color("#cdd6f4"))
+	return table.New(
+		table.WithColumns([]table.Column{{Title: "Key"}, {Title: "Value"}}),
+		table.WithRows(variableRows(file, values)),
+		table.WithFocused(true),
+		table.WithStyles(styles),
+	)
+}
+
+// layoutTable gives the key column a third of the width and the value column the rest
+func layoutTable(m *uiModel) {
+	// title, help and status lines and the header of the table
+	m.table.SetHeight(max(1, m.height-6))
+	width := max(20, m.width-4)
+	m.table.SetColumns([]table.Column{
+		{Title: "Key", Width: width / 3},
+		{Title: "Value", Width: width - width/3},
+	})
+}
+
 func (m uiModel) View() string {
+	var view string
 	switch m.active {
-	case List:
-		return renderList(m)
 	case Create:
 		return renderCreate(m)
+	case Update:
+		view = renderTable(m)
 	default:
-		return renderList(m)
+		view = m.list.View()
 	}
+	return lipgloss.JoinVertical(lipgloss.Left, view, statusStyle.Render(m.status))
 }
 
-func renderList(m uiModel) string {
-	return lipgloss.Place(
-		m.width,
-		m.height,
-		lipgloss.Left,
-		lipgloss.Top,
-		m.list.View())
+func renderTable(m uiModel) string {
+	title := titleStyle.Render(fmt.Sprintf("%s · %s", m.selected.Name, m.selected.Filename))
+	help := helpStyle.Render("↑/↓ move • enter edit value • a add • r rename • x delete • esc back")
+	return lipgloss.NewStyle().Padding(0, 1).Render(lipgloss.JoinVertical(lipgloss.Left, title, "", m.table.View(), help))
 }
 
 func renderCreate(m uiModel) string {
@@ -102,29 +424,13 @@ func renderCreate(m uiModel) string {
 		m.create.View())
 }
 
-func Start(loadedProfiles []model.Profile) {
-	var profiles []list.Profile
-
-	for _, v := range loadedProfiles {
-		r := list.Profile{
-			Name:      v.Name,
-			Variables: len(v.Variables), // TODO
-		}
-		profiles = append(profiles, r)
-	}
-
-	f, err := tea.LogToFile("debug.log", "debug")
-	if err != nil {
-		fmt.Println("fatal:", err)
-		os.Exit(1)
-	}
-	defer f.Close()
-
-	m := uiModel{list: list.New(profiles, 0, 0, keys), active: List}
+func Start(root string) {
+	m := uiModel{root: root, list: list.New(nil, 0, 0, keys), active: List}
+	reloadProfiles(&m)
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
-	_, err = p.Run()
+	_, err := p.Run()
 	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
@@ -1,58 +1,185 @@
 package managetui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"goful/core/client"
 	"goful/core/client/builder"
+	"goful/core/exporter"
+	"goful/core/history"
+	"goful/core/importer"
+	"goful/core/loader"
 	"goful/core/model"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	"goful/core/print"
+	"goful/core/redact"
 
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
-func doRequest(r Request) tea.Cmd {
-	// TODO handle errors
+func doRequest(ctx context.Context, r Request, profile model.Profile) tea.Cmd {
 	return func() tea.Msg {
-		req, err := builder.BuildRequest(r.Mold, model.Profile{})
+		req, err := builder.BuildRequest(ctx, r.Mold, profile)
+		if errors.Is(err, context.Canceled) {
+			return RequestFinishedMsg{Canceled: true}
+		}
 		if err != nil {
-			return RequestFinishedMsg(fmt.Sprintf("failed to build request err: %v", err))
+			log.Error().Err(err).Msgf("Failed to build request %s", r.Name)
+			return RequestFinishedMsg{
+				Entry: history.Entry{
+					Name:    r.Name,
+					Profile: profile.Name,
+					Time:    time.Now(),
+					Error:   fmt.Sprintf("failed to build request err: %v", err),
+				},
+			}
+		}
+		return executeRequest(ctx, r.Name, profile.Name, req)
+	}
+}
+
+// rerunHistoryEntry sends the recorded request again. Credentials were redacted from the recorded request,
+// so such a request is built again from the workspace request of the same name and the profile of the entry instead.
+func rerunHistoryEntry(ctx context.Context, entry history.Entry, requests []list.Item, profile model.Profile) tea.Cmd {
+	if !entry.Redacted {
+		return func() tea.Msg {
+			return executeRequest(ctx, entry.Name, entry.Profile, entry.Request)
 		}
-		resp, err := client.DoRequest(req)
+	}
+	failed := func(err string) tea.Cmd {
+		return func() tea.Msg {
+			return RequestFinishedMsg{
+				Entry: history.Entry{
+					Name:    entry.Name,
+					Profile: entry.Profile,
+					Time:    time.Now(),
+					Error:   err,
+				},
+			}
+		}
+	}
+	// the request is built again with the profile it was recorded with, the active one may differ
+	if entry.Profile != profile.Name {
+		entryProfile, err := loader.ResolveProfile(viper.GetString("workspace"), entry.Profile, nil)
 		if err != nil {
-			return RequestFinishedMsg(fmt.Sprintf("failed to do request err: %v", err))
+			log.Error().Err(err).Msgf("Failed to resolve profile %s of history entry #%d", entry.Profile, entry.Id)
+			return failed(fmt.Sprintf("request %s was recorded with redacted credentials and its profile %s could not be resolved: %v", entry.Name, entry.Profile, err))
 		}
+		redact.SetVariables(profile.Variables, entryProfile.Variables)
+		profile = entryProfile
+	}
+	for _, item := range requests {
+		if r, ok := item.(Request); ok && r.Name == entry.Name {
+			return doRequest(ctx, r, profile)
+		}
+	}
+	return failed(fmt.Sprintf("request %s was recorded with redacted credentials and is no longer in the workspace", entry.Name))
+}
 
-		printed, err := print.SprintPrettyFullResponse(resp)
+// executeRequest does an already built request and records it into the workspace history.
+// Cancelled requests are not recorded.
+func executeRequest(ctx context.Context, name string, profileName string, req model.Request) tea.Msg {
+	started := time.Now()
+	resp, err := client.DoRequest(ctx, req)
+	if errors.Is(err, context.Canceled) {
+		log.Info().Msgf("Cancelled request %s", name)
+		return RequestFinishedMsg{Canceled: true}
+	}
+	return RequestFinishedMsg{
+		Entry: history.Record(viper.GetString("workspace"), history.NewEntry(name, profileName, req, resp, err, started)),
+	}
+}
+
+func loadHistory() tea.Cmd {
+	return func() tea.Msg {
+		entries, err := history.NewStore(viper.GetString("workspace")).ReadAll()
 		if err != nil {
-			return RequestFinishedMsg(fmt.Sprintf("failed to sprint response err: %v", err))
+			log.Error().Err(err).Msg("Failed to read history")
+			nowTime := time.Now().Format("15:04:05")
+			return StatusMessage(fmt.Sprintf("%s Failed to read history", nowTime))
 		}
-		return RequestFinishedMsg(printed)
+		return ShowHistoryMsg{
+			Entries: entries,
+		}
+	}
+}
+
+func responseTitle(entry history.Entry) string {
+	title := entry.Name
+	if entry.Id > 0 {
+		title = fmt.Sprintf("#%d %s", entry.Id, title)
+	}
+	if entry.Response == nil {
+		return fmt.Sprintf("%s :: failed", title)
 	}
+	return fmt.Sprintf("%s :: %s :: %v", title, entry.Response.Status, entry.Duration.Round(time.Millisecond))
 }
 
-func handlePostAction(m uiModel) {
-	switch m.postAction.Type {
-	case CreateSimpleRequest:
-		createSimpleRequestFile(m.postAction.Payload.(string))
-	case CreateComplexRequest:
-		createComplexRequestFile(m.postAction.Payload.(string))
-	case EditRequest:
-		openFileToEditor(m.postAction.Payload.(Request))
-	case PrintRequest:
-		fmt.Printf("%s\n", m.postAction.Payload.(string))
+func sprintHistoryEntry(entry history.Entry) string {
+	if entry.Response == nil {
+		if entry.Request.Url == "" {
+			return entry.Error
+		}
+		return redact.String(fmt.Sprintf("%s %s\nfailed to do request err: %s", entry.Request.Method, entry.Request.Url, entry.Error))
+	}
+	printed, err := print.SprintPrettyFullResponse(entry.Response)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to print response of history entry #%d", entry.Id)
+		return string(entry.Response.Body)
+	}
+	return printed
+}
+
+func exportRequestToClipboard(r Request, profile model.Profile) tea.Cmd {
+	return func() tea.Msg {
+		nowTime := time.Now().Format("15:04:05")
+		format := viper.GetString("export.format")
+		var exported string
+		if format == exporter.Http {
+			var skipped []string
+			var err error
+			exported, skipped, err = exporter.SprintHttpFile([]model.RequestMold{r.Mold}, profile.Variables, profile.Defaults, viper.GetBool("export.redact"))
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to export request %s", r.Name)
+				return StatusMessage(fmt.Sprintf("%s Failed to export request", nowTime))
+			}
+			if len(skipped) > 0 {
+				return StatusMessage(fmt.Sprintf("%s Starlark requests can not be exported as %s", nowTime, format))
+			}
+		} else {
+			req, err := builder.BuildRequest(context.Background(), r.Mold, profile)
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to build request %s", r.Name)
+				return StatusMessage(fmt.Sprintf("%s Failed to build request", nowTime))
+			}
+			exported, err = exporter.Export(req, format, viper.GetBool("export.redact"))
+			if err != nil {
+				log.Error().Err(err).Msgf("Failed to export request %s", r.Name)
+				return StatusMessage(fmt.Sprintf("%s Failed to export request", nowTime))
+			}
+		}
+		err := clipboard.WriteAll(exported)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to write clipboard")
+			return StatusMessage(fmt.Sprintf("%s Failed to write clipboard", nowTime))
+		}
+		return StatusMessage(fmt.Sprintf("%s Copied %s as %s", nowTime, r.Title(), format))
 	}
 }
 
-func createSimpleRequestFile(name string) {
+func createSimpleRequestFile(name string) tea.Cmd {
 	if len(name) == 0 {
-		return
+		return nil
 	}
 
 	filename := fmt.Sprintf("%s.yaml", name)
@@ -74,12 +201,12 @@ headers:
 body: >
 `, name)
 
-	createFileAndOpenToEditor(filename, content)
+	return createFileAndOpenToEditor(filename, content)
 }
 
-func createComplexRequestFile(name string) {
+func createComplexRequestFile(name string) tea.Cmd {
 	if len(name) == 0 {
-		return
+		return nil
 	}
 
 	filename := fmt.Sprintf("%s.star", name)
@@ -101,71 +228,72 @@ headers = {}
 body = {}
 `, name)
 
-	createFileAndOpenToEditor(filename, content)
+	return createFileAndOpenToEditor(filename, content)
 }
 
-func createFileAndOpenToEditor(filename string, content string) {
-
-	if len(filename) > 0 {
-		// TODO get workdir from configuration
-		file, err := os.Create(filepath.Join("tmp", filename))
-		if err == nil {
-			defer file.Close()
-			// todo handle err
-			_, err = file.WriteString(content)
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to write file")
-			}
-			file.Sync()
-			filename := file.Name()
-			editor := viper.GetString("editor")
-			if editor == "" {
-				log.Error().Msg("Editor is not configured through configuration file or $editor environment variable.")
-			}
-
-			log.Info().Msgf("Opening file %s\n", filename)
-			cmd := exec.Command(editor, filename)
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
-
-			err = cmd.Run()
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to open file with editor")
-			}
-			log.Printf("Successfully edited file %v", file.Name())
-			fmt.Printf("Saved new request to file %v", file.Name())
-		} else {
-			log.Error().Err(err).Msg("Failed to create file")
+func createFileAndOpenToEditor(filename string, content string) tea.Cmd {
+	path := filepath.Join(viper.GetString("workspace"), filename)
+	err := os.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to create file %s", path)
+		return func() tea.Msg {
+			nowTime := time.Now().Format("15:04:05")
+			return StatusMessage(fmt.Sprintf("%s Failed to create %s", nowTime, filename))
 		}
 	}
-
+	log.Info().Msgf("Created request file %s", path)
+	return openFileToEditor(viper.GetString("workspace"), filename, true)
 }
 
-func openFileToEditor(r Request) {
-	if r.Mold.Filename != "" {
-		fileName := filepath.Join(r.Mold.Root, r.Mold.Filename)
+// openFileToEditor suspends the program while the file is open in the editor and resumes it when the editor exits
+func openFileToEditor(root string, filename string, created bool) tea.Cmd {
+	path := filepath.Join(root, filename)
+	editor := viper.GetString("editor")
+	if editor == "" {
+		log.Error().Msg("Editor is not configured through configuration file or $EDITOR environment variable.")
+		return func() tea.Msg {
+			nowTime := time.Now().Format("15:04:05")
+			return StatusMessage(fmt.Sprintf("%s Editor is not configured, set editor in the configuration or $EDITOR", nowTime))
+		}
+	}
 
-		log.Info().Msgf("About to open request file %v\n", fileName)
-		if len(fileName) > 0 {
-			// TODO handle err
-			editor := viper.GetString("editor")
-			if editor == "" {
-				log.Error().Msg("Editor is not configured through configuration file or $EDITOR environment variable.")
-			}
+	log.Info().Msgf("About to open request file %v", path)
+	cmd := exec.Command(editor, path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return EditorFinishedMsg{
+			Filename: filename,
+			Created:  created,
+			Err:      err,
+		}
+	})
+}
 
-			cmd := exec.Command(editor, fileName)
-			cmd.Stdin = os.Stdin
-			cmd.Stdout = os.Stdout
-			cmd.Stderr = os.Stderr
+// refreshEditedRequest reads the edited file again and updates it in the list, a created request gets selected
+func refreshEditedRequest(m *uiModel, msg EditorFinishedMsg) string {
+	nowTime := time.Now().Format("15:04:05")
+	if msg.Err != nil {
+		log.Error().Err(msg.Err).Msg("Failed to open file with editor")
+		return fmt.Sprintf("%s Editor exited with error: %v", nowTime, msg.Err)
+	}
+	log.Info().Msgf("Successfully edited file %v", msg.Filename)
 
-			err := cmd.Run()
-			if err != nil {
-				log.Error().Err(err).Msg("Failed to open file with editor")
+	mold, err := loader.ReadRequest(viper.GetString("workspace"), msg.Filename)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to read edited file %s", msg.Filename)
+		return fmt.Sprintf("%s Failed to read %s", nowTime, msg.Filename)
+	}
+	applyRequestChanges(m, []loader.RequestChange{{Filename: msg.Filename, Mold: mold}})
+	if mold == nil {
+		return fmt.Sprintf("%s %s is not a valid request, it needs a name", nowTime, msg.Filename)
+	}
+	if msg.Created {
+		for i, item := range m.list.VisibleItems() {
+			if r, ok := item.(Request); ok && r.Mold.Filename == msg.Filename {
+				m.list.Select(i)
 			}
-			log.Info().Msgf("Successfully edited file %v", fileName)
 		}
 	}
+	return fmt.Sprintf("%s Saved %s", nowTime, msg.Filename)
 }
 
 func renameRequest(newName string, r Request) (Request, bool) {
@@ -232,15 +360,44 @@ func copyRequest(name string, r Request) (Request, bool) {
 
 }
 
+func pasteCurlRequest() tea.Cmd {
+	return func() tea.Msg {
+		nowTime := time.Now().Format("15:04:05")
+		content, err := clipboard.ReadAll()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to read clipboard")
+			return StatusMessage(fmt.Sprintf("%s Failed to read clipboard", nowTime))
+		}
+		yamlRequest, err := importer.ParseCurl(content)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to parse curl command from clipboard")
+			return StatusMessage(fmt.Sprintf("%s Clipboard has no valid curl command: %v", nowTime, err))
+		}
+		return PasteCurlRequestMsg{
+			Request: yamlRequest,
+		}
+	}
+}
+
+func saveCurlRequest(name string, yamlRequest *model.YamlRequest, profile model.Profile) (Request, bool) {
+	yamlRequest.Name = name
+	mold, err := importer.SaveYamlRequest(viper.GetString("workspace"), yamlRequest)
+	if err != nil {
+		log.Error().Err(err).Msgf("Failed to save request %s", name)
+		return Request{}, false
+	}
+	return newRequest(mold, profile), true
+}
+
 func changeMoldName(name string, m *model.RequestMold) {
 	if m.Yaml != nil {
-		m.Filename = fmt.Sprintf("%s.yaml", name)
+		m.Filename = filepath.Join(filepath.Dir(m.Filename), fmt.Sprintf("%s.yaml", name))
 		m.Yaml.Name = name
 		pattern := regexp.MustCompile(`(?mU)^name:(.*)$`)
 		nameChanged := pattern.ReplaceAllString(m.Yaml.Raw, fmt.Sprintf("name: %s", name))
 		m.Yaml.Raw = nameChanged
 	} else if m.Starlark != nil {
-		m.Filename = fmt.Sprintf("%s.star", name)
+		m.Filename = filepath.Join(filepath.Dir(m.Filename), fmt.Sprintf("%s.star", name))
 		pattern := regexp.MustCompile(`(?mU)^.*meta:name:(.*)$`)
 		nameChanged := pattern.ReplaceAllString(m.Starlark.Script, fmt.Sprintf("meta:name: %s", name))
 		m.Starlark.Script = nameChanged
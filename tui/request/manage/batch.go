@@ -0,0 +1,223 @@
+package managetui
+
+import (
+	"context"
+	"fmt"
+	"goful/core/model"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mistakenelf/teacup/statusbar"
+)
+
+type batchRowState int
+
+const (
+	Pending batchRowState = iota
+	Running
+	Finished
+	Canceled
+)
+
+const batchTickInterval = 100 * time.Millisecond
+
+type batchRow struct {
+	request  Request
+	state    batchRowState
+	started  time.Time
+	finished RequestFinishedMsg
+}
+
+// batch runs marked requests concurrently, its events are delivered to the ui one at a time through events
+type batch struct {
+	rows        []batchRow
+	cursor      int
+	parallelism int
+	running     bool
+	events      chan tea.Msg
+	cancel      context.CancelFunc
+}
+
+func markedRequests(items []Request) []Request {
+	var marked []Request
+	for _, r := range items {
+		if r.Marked {
+			marked = append(marked, r)
+		}
+	}
+	return marked
+}
+
+func newBatch(requests []Request, parallelism int) batch {
+	rows := make([]batchRow, len(requests))
+	for i, r := range requests {
+		rows[i] = batchRow{request: r}
+	}
+	return batch{
+		rows:        rows,
+		parallelism: max(1, parallelism),
+		running:     true,
+		// room for every event so that workers never block on a ui that has stopped listening
+		events: make(chan tea.Msg, 2*len(requests)),
+	}
+}
+
+// startBatch runs the requests of the batch with at most parallelism of them in flight at a time
+func startBatch(ctx context.Context, b batch, profile model.Profile) tea.Cmd {
+	go func() {
+		semaphore := make(chan struct{}, b.parallelism)
+		var wg sync.WaitGroup
+		for i, row := range b.rows {
+			wg.Add(1)
+			go func(i int, r Request) {
+				defer wg.Done()
+				select {
+				case semaphore <- struct{}{}:
+				case <-ctx.Done():
+					b.events <- BatchRequestFinishedMsg{Index: i, Finished: RequestFinishedMsg{Canceled: true}}
+					return
+				}
+				defer func() { <-semaphore }()
+
+				b.events <- BatchRequestStartedMsg{Index: i}
+				finished := doRequest(ctx, r, profile)().(RequestFinishedMsg)
+				b.events <- BatchRequestFinishedMsg{Index: i, Finished: finished}
+			}(i, row.request)
+		}
+		wg.Wait()
+		close(b.events)
+	}()
+	return tea.Batch(waitForBatchEvent(b.events), batchTick())
+}
+
+func waitForBatchEvent(events chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-events
+		if !ok {
+			return BatchFinishedMsg{}
+		}
+		return msg
+	}
+}
+
+func batchTick() tea.Cmd {
+	return tea.Tick(batchTickInterval, func(time.Time) tea.Msg {
+		return BatchTickMsg{}
+	})
+}
+
+func (b batch) summary() string {
+	counts := make(map[batchRowState]int)
+	for _, row := range b.rows {
+		counts[row.state]++
+	}
+	summary := fmt.Sprintf("%d/%d finished", counts[Finished], len(b.rows))
+	if counts[Running] > 0 {
+		summary += fmt.Sprintf(", %d running", counts[Running])
+	}
+	if counts[Canceled] > 0 {
+		summary += fmt.Sprintf(", %d cancelled", counts[Canceled])
+	}
+	return summary
+}
+
+func renderBatch(m uiModel) string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(fmt.Sprintf("Running %d requests, %d at a time", len(m.batch.rows), m.batch.parallelism)))
+	sb.WriteString("\n\n")
+
+	nameWidth := 0
+	for _, row := range m.batch.rows {
+		nameWidth = max(nameWidth, lipgloss.Width(row.request.Name))
+	}
+
+	for i, row := range m.batch.rows {
+		var state, elapsed string
+		stateStyle := batchPendingStyle
+		switch row.state {
+		case Pending:
+			state = "pending"
+		case Running:
+			state = "running"
+			stateStyle = batchRunningStyle
+			elapsed = time.Since(row.started).Round(batchTickInterval).String()
+		case Canceled:
+			state = "cancelled"
+		case Finished:
+			entry := row.finished.Entry
+			elapsed = entry.Duration.Round(time.Millisecond).String()
+			if entry.Response == nil {
+				state = "failed"
+				stateStyle = batchFailedStyle
+			} else {
+				state = entry.Response.Status
+				stateStyle = batchOkStyle
+				if entry.Response.StatusCode >= 400 {
+					stateStyle = batchFailedStyle
+				}
+			}
+		}
+
+		cursor := "  "
+		if i == m.batch.cursor {
+			cursor = "> "
+		}
+		sb.WriteString(fmt.Sprintf("%s%-*s  %s  %8s  %s %s\n",
+			cursor,
+			nameWidth,
+			row.request.Name,
+			stateStyle.Render(fmt.Sprintf("%-22s", state)),
+			elapsed,
+			row.request.Method,
+			row.request.Url,
+		))
+	}
+
+	sb.WriteString("\n")
+	help := "↑/k up • ↓/j down • enter open response • q/esc back"
+	if m.batch.running {
+		help = "↑/k up • ↓/j down • enter open response • esc cancel"
+	}
+	sb.WriteString(helpDescStyle.Render(fmt.Sprintf("%s :: %s", m.batch.summary(), help)))
+
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		lipgloss.NewStyle().Height(m.height-statusbar.Height).Padding(1, 2).Render(sb.String()),
+		m.statusbar.View(),
+	)
+}
+
+func updateBatch(m uiModel, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case tea.KeyCtrlC.String():
+		m.batch.cancel()
+		return m, tea.Quit
+	case "up", "k":
+		m.batch.cursor = max(0, m.batch.cursor-1)
+	case "down", "j":
+		m.batch.cursor = min(len(m.batch.rows)-1, m.batch.cursor+1)
+	case tea.KeyEnter.String():
+		row := m.batch.rows[m.batch.cursor]
+		if row.state == Finished {
+			m.active = Response
+			m.responseParent = Batch
+			m.response.Title = responseTitle(row.finished.Entry)
+			m.response.Viewport.SetContent(sprintHistoryEntry(row.finished.Entry))
+			m.response.Viewport.GotoTop()
+		}
+	case tea.KeyEsc.String():
+		if m.batch.running {
+			m.batch.cancel()
+			return m, nil
+		}
+		m.active = List
+	case "q":
+		if !m.batch.running {
+			m.active = List
+		}
+	}
+	return m, nil
+}
@@ -3,6 +3,7 @@ package managetui
 import (
 	"fmt"
 	"goful/core/client/validator"
+	"slices"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -19,10 +20,30 @@ var selectModeKeys = []key.Binding{
 		key.WithKeys(tea.KeyEnter.String()),
 		key.WithHelp(tea.KeyEnter.String(), "run request"),
 	),
+	key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "mark"),
+	),
+	key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "run marked"),
+	),
+	key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy as snippet"),
+	),
+	key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "history"),
+	),
 	key.NewBinding(
 		key.WithKeys("i"),
 		key.WithHelp("i", "edit mode"),
 	),
+	key.NewBinding(
+		key.WithKeys(tea.KeyTab.String()),
+		key.WithHelp(tea.KeyTab.String(), "switch pane"),
+	),
 	key.NewBinding(
 		key.WithKeys("q", tea.KeyCtrlC.String()),
 		key.WithHelp("q/ctrl+c", "quit"),
@@ -58,6 +79,10 @@ var editModeKeys = []key.Binding{
 		key.WithKeys("c"),
 		key.WithHelp("c", "copy request"),
 	),
+	key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "paste curl"),
+	),
 	key.NewBinding(
 		key.WithKeys(tea.KeyEsc.String()),
 		key.WithHelp(tea.KeyEsc.String(), "view mode"),
@@ -108,6 +133,28 @@ func newSelectDelegate() list.DefaultDelegate {
 						Request: request,
 					}
 				})
+			case "y":
+				return tea.Cmd(func() tea.Msg {
+					return ExportRequestMsg{
+						Request: request,
+					}
+				})
+			case " ":
+				request.Marked = !request.Marked
+				return setRequest(m, request.Mold.Filename, request)
+			case "R":
+				var requests []Request
+				for _, item := range m.Items() {
+					if r, ok := item.(Request); ok {
+						requests = append(requests, r)
+					}
+				}
+				marked := markedRequests(requests)
+				return tea.Cmd(func() tea.Msg {
+					return RunMarkedRequestsMsg{
+						Requests: marked,
+					}
+				})
 			}
 		}
 
@@ -143,8 +190,9 @@ func newEditModeDelegate() list.DefaultDelegate {
 			case "x":
 				deleted := request.Mold.DeleteFromFS()
 				if deleted {
-					index := m.Index()
-					m.RemoveItem(index)
+					if index := requestIndex(m, request.Mold.Filename); index >= 0 {
+						m.RemoveItem(index)
+					}
 					return tea.Cmd(func() tea.Msg {
 						nowTime := time.Now().Format("15:04:05")
 						return StatusMessage(fmt.Sprintf("%s Deleted %s", nowTime, request.Title()))
@@ -196,3 +244,28 @@ func newEditModeDelegate() list.DefaultDelegate {
 	return d
 
 }
+
+// setRequest replaces the request read from filename
+func setRequest(m *list.Model, filename string, request Request) tea.Cmd {
+	if index := requestIndex(m, filename); index >= 0 {
+		return m.SetItem(index, request)
+	}
+	return nil
+}
+
+// requestIndex returns the index among all items of the request read from filename, or -1. The index of the list is
+// the position among the visible items when a filter is active, so it can not be used to change the items.
+func requestIndex(m *list.Model, filename string) int {
+	return slices.IndexFunc(m.Items(), func(item list.Item) bool {
+		r, ok := item.(Request)
+		return ok && r.Mold.Filename == filename
+	})
+}
+
+// selectedRequestIndex returns the index among all items of the selected request, or -1 when there is none
+func selectedRequestIndex(m *list.Model) int {
+	if selected, ok := m.SelectedItem().(Request); ok {
+		return requestIndex(m, selected.Mold.Filename)
+	}
+	return -1
+}
@@ -0,0 +1,78 @@
+package managetui
+
+import (
+	"goful/core/model"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestRequest(name string) Request {
+	return Request{
+		Name:   name,
+		Url:    "http://foobar.com/" + name,
+		Method: "GET",
+		Mold: model.RequestMold{
+			Yaml:     &model.YamlRequest{Name: name, Url: "http://foobar.com/" + name, Method: "GET"},
+			Filename: name + ".yaml",
+		},
+	}
+}
+
+// update sends msg to the list followed by the filter matches the resulting commands produce
+func update(m list.Model, msg tea.Msg) list.Model {
+	m, cmd := m.Update(msg)
+	for _, next := range run(cmd) {
+		if _, ok := next.(list.FilterMatchesMsg); ok {
+			m = update(m, next)
+		}
+	}
+	return m
+}
+
+func run(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, run(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
+
+func TestMarkWithFilterActive(t *testing.T) {
+	items := []list.Item{newTestRequest("alpha"), newTestRequest("beta"), newTestRequest("gamma")}
+	m := list.New(items, newSelectDelegate(), 80, 20)
+
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m = update(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gamma")})
+	m = update(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.FilterState() != list.FilterApplied || len(m.VisibleItems()) != 1 {
+		t.Fatalf("expected the filter to show gamma only, got %v", m.VisibleItems())
+	}
+
+	m = update(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+
+	var names []string
+	var marked []string
+	for _, item := range m.Items() {
+		r := item.(Request)
+		names = append(names, r.Name)
+		if r.Marked {
+			marked = append(marked, r.Mold.Filename)
+		}
+	}
+	if !cmp.Equal(names, []string{"alpha", "beta", "gamma"}) {
+		t.Errorf("got %v, wanted the items unchanged", names)
+	}
+	if !cmp.Equal(marked, []string{"gamma.yaml"}) {
+		t.Errorf("got %v, wanted %v", marked, []string{"gamma.yaml"})
+	}
+}
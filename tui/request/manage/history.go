@@ -0,0 +1,144 @@
+package managetui
+
+import (
+	"fmt"
+	"goful/core/history"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var historyKeys = []key.Binding{
+	key.NewBinding(
+		key.WithKeys(tea.KeyEnter.String(), "p"),
+		key.WithHelp(tea.KeyEnter.String()+"/p", "open response"),
+	),
+	key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "run again"),
+	),
+	key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "mark/compare"),
+	),
+	key.NewBinding(
+		key.WithKeys("q", tea.KeyEsc.String()),
+		key.WithHelp("q/esc", "back"),
+	),
+}
+
+type HistoryEntry struct {
+	Entry history.Entry
+}
+
+func (i HistoryEntry) Title() string {
+	name := i.Entry.Name
+	if name == "" {
+		name = i.Entry.Request.Url
+	}
+	return fmt.Sprintf("#%d %s", i.Entry.Id, name)
+}
+
+func (i HistoryEntry) Description() string {
+	var status string
+	if i.Entry.Response != nil {
+		status = i.Entry.Response.Status
+		color := historyStatusOkColor
+		if i.Entry.Response.StatusCode >= 400 {
+			color = historyStatusErrorColor
+		}
+		status = lipgloss.NewStyle().Foreground(color).Render(status)
+	} else {
+		status = lipgloss.NewStyle().Foreground(historyStatusErrorColor).Render("failed")
+	}
+
+	return fmt.Sprintf("%s %s %s :: %s :: %s in %v",
+		i.Entry.Time.Local().Format("2006-01-02 15:04:05"),
+		i.Entry.Request.Method,
+		i.Entry.Request.Url,
+		i.Entry.Profile,
+		status,
+		i.Entry.Duration.Round(time.Millisecond),
+	)
+}
+
+func (i HistoryEntry) FilterValue() string {
+	status := i.Entry.Error
+	if i.Entry.Response != nil {
+		status = i.Entry.Response.Status
+	}
+	return fmt.Sprintf("%d %s %s %s %s %s", i.Entry.Id, i.Entry.Name, i.Entry.Request.Method, i.Entry.Request.Url, i.Entry.Profile, status)
+}
+
+// newHistoryList lists entries newest first
+func newHistoryList(entries []history.Entry, width int, height int) list.Model {
+	items := make([]list.Item, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		items = append(items, HistoryEntry{Entry: entries[i]})
+	}
+
+	historyList := list.New(items, newHistoryDelegate(), width, height)
+	historyList.Title = "History"
+	historyList.Styles.Title = titleStyle
+	historyList.Help.Styles.FullKey = helpKeyStyle
+	historyList.Help.Styles.FullDesc = helpDescStyle
+	historyList.Help.Styles.ShortKey = helpKeyStyle
+	historyList.Help.Styles.ShortDesc = helpDescStyle
+	historyList.Help.Styles.ShortSeparator = helpSeparatorStyle
+	historyList.Help.Styles.FullSeparator = helpSeparatorStyle
+	// q and esc return to the request list
+	historyList.KeyMap.Quit.SetEnabled(false)
+	return historyList
+}
+
+func newHistoryDelegate() list.DefaultDelegate {
+	d := newBaseDelegate()
+
+	d.UpdateFunc = func(msg tea.Msg, m *list.Model) tea.Cmd {
+		var entry HistoryEntry
+		if i, ok := m.SelectedItem().(HistoryEntry); ok {
+			entry = i
+		} else {
+			return nil
+		}
+
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch keypress := msg.String(); keypress {
+			case "enter", "p":
+				return tea.Cmd(func() tea.Msg {
+					return OpenHistoryEntryMsg{
+						Entry: entry.Entry,
+					}
+				})
+			case "r":
+				return tea.Cmd(func() tea.Msg {
+					return RerunHistoryEntryMsg{
+						Entry: entry.Entry,
+					}
+				})
+			case "d":
+				return tea.Cmd(func() tea.Msg {
+					return DiffHistoryEntryMsg{
+						Entry: entry.Entry,
+					}
+				})
+			}
+		}
+
+		return nil
+	}
+
+	d.ShortHelpFunc = func() []key.Binding {
+		return historyKeys
+	}
+
+	d.FullHelpFunc = func() [][]key.Binding {
+		return [][]key.Binding{historyKeys}
+	}
+
+	return d
+}
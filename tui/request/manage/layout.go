@@ -0,0 +1,170 @@
+package managetui
+
+import (
+	"goful/core/print"
+	"goful/core/redact"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mistakenelf/teacup/statusbar"
+)
+
+type Pane int
+
+const (
+	ListPane Pane = iota
+	SourcePane
+	ResponsePane
+)
+
+const (
+	// below this width only the focused pane is shown
+	splitLayoutMinWidth  = 90
+	listPaneWidthPercent = 40
+)
+
+// paneSizes are outer sizes of the panes, borders included
+type paneSizes struct {
+	listWidth      int
+	rightWidth     int
+	height         int
+	sourceHeight   int
+	responseHeight int
+}
+
+func (p Pane) next() Pane {
+	return (p + 1) % 3
+}
+
+func (p Pane) prev() Pane {
+	return (p + 2) % 3
+}
+
+func isSplitLayout(m uiModel) bool {
+	return m.width >= splitLayoutMinWidth
+}
+
+// layoutPanes sizes the list, source and response panes to the window. Each pane is surrounded by a border.
+func layoutPanes(m *uiModel) {
+	height := m.height - statusbar.Height
+	listWidth, rightWidth := m.width, m.width
+	sourceHeight, responseHeight := height, height
+	if isSplitLayout(*m) {
+		listWidth = m.width * listPaneWidthPercent / 100
+		rightWidth = m.width - listWidth
+		sourceHeight = height / 2
+		responseHeight = height - sourceHeight
+	}
+
+	m.panes = paneSizes{
+		listWidth:      listWidth,
+		rightWidth:     rightWidth,
+		height:         height,
+		sourceHeight:   sourceHeight,
+		responseHeight: responseHeight,
+	}
+	m.list.SetSize(max(0, listWidth-2), max(0, height-2))
+	m.source.Viewport.Width = max(0, rightWidth-2)
+	m.source.Viewport.Height = max(0, sourceHeight-2-m.source.VerticalMarginHeight())
+	m.lastResponse.Viewport.Width = max(0, rightWidth-2)
+	m.lastResponse.Viewport.Height = max(0, responseHeight-2-m.lastResponse.VerticalMarginHeight())
+}
+
+// syncSource shows the source of the selected request in the source pane when the selection has changed
+func syncSource(m *uiModel) {
+	selected, ok := m.list.SelectedItem().(Request)
+	if !ok {
+		if m.sourceKey != "" {
+			m.sourceKey = ""
+			m.source.Title = "Source"
+			m.source.Viewport.SetContent("")
+		}
+		return
+	}
+	key := filepath.Join(selected.Mold.Root, selected.Mold.Filename) + "\x00" + selected.Mold.Raw()
+	if key == m.sourceKey {
+		return
+	}
+	m.sourceKey = key
+	m.source.Title = selected.Mold.Filename
+	m.source.Viewport.SetContent(sprintSource(selected))
+	m.source.Viewport.GotoTop()
+}
+
+// sprintSource highlights the redacted source of a request, it is redacted first as highlighting may split values
+func sprintSource(r Request) string {
+	raw := redact.String(r.Mold.Raw())
+	var formatted string
+	var err error
+	switch r.Mold.ContentType {
+	case "yaml":
+		formatted, err = print.SprintYaml(raw)
+	case "star":
+		formatted, err = print.SprintStar(raw)
+	}
+
+	if formatted == "" || err != nil {
+		formatted = raw
+	}
+	return formatted
+}
+
+// updateFocusedPane passes messages to the viewport of a focused source or response pane
+func updateFocusedPane(m uiModel, msg tea.Msg) (uiModel, tea.Cmd) {
+	var cmd tea.Cmd
+	switch m.focus {
+	case SourcePane:
+		m.source, cmd = m.source.Update(msg)
+	case ResponsePane:
+		m.lastResponse, cmd = m.lastResponse.Update(msg)
+	}
+	return m, cmd
+}
+
+func renderPanes(m uiModel) string {
+	paneStyle := func(pane Pane, width int, height int) lipgloss.Style {
+		style := paneStyle
+		if m.focus == pane {
+			style = focusedPaneStyle
+		}
+		return style.Copy().Width(max(0, width-2)).Height(max(0, height-2))
+	}
+
+	listView := paneStyle(ListPane, m.panes.listWidth, m.panes.height).Render(m.list.View())
+	sourceView := paneStyle(SourcePane, m.panes.rightWidth, m.panes.sourceHeight).Render(m.source.View())
+	responseView := paneStyle(ResponsePane, m.panes.rightWidth, m.panes.responseHeight).Render(renderResponsePane(m))
+
+	var panes string
+	if isSplitLayout(m) {
+		panes = lipgloss.JoinHorizontal(lipgloss.Top, listView, lipgloss.JoinVertical(lipgloss.Left, sourceView, responseView))
+	} else {
+		switch m.focus {
+		case ListPane:
+			panes = listView
+		case SourcePane:
+			panes = sourceView
+		case ResponsePane:
+			panes = responseView
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Top,
+		lipgloss.NewStyle().Height(m.height-statusbar.Height).Render(panes),
+		m.statusbar.View(),
+	)
+}
+
+func renderResponsePane(m uiModel) string {
+	if m.active == Stopwatch {
+		return lipgloss.Place(
+			m.lastResponse.Viewport.Width,
+			m.lastResponse.Viewport.Height+m.lastResponse.VerticalMarginHeight(),
+			lipgloss.Center,
+			lipgloss.Center,
+			stopwatchStyle.Render("Running request... :: Elapsed time: "+m.stopwatch.View()+" :: esc to cancel"),
+		)
+	}
+	return m.lastResponse.View()
+}
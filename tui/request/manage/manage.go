@@ -1,8 +1,14 @@
 package managetui
 
 import (
+	"context"
 	"fmt"
+	"goful/core/client/builder"
 	"goful/core/client/validator"
+	"goful/core/diff"
+	"goful/core/history"
+	"goful/core/importer"
+	"goful/core/loader"
 	"goful/core/model"
 	"goful/core/print"
 	"time"
@@ -17,6 +23,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mistakenelf/teacup/statusbar"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 )
 
 type ActiveView int
@@ -27,35 +34,32 @@ const (
 	Duplicate
 	Preview
 	Stopwatch
+	History
+	Response
+	Batch
 )
 
 type Mode int
 
-type PostAction struct {
-	Type             string
-	Payload          interface{}
-	AddtionalContext interface{}
-}
-
 const (
 	Select Mode = iota
 	Edit
 )
 
 const (
-	CreateSimpleRequestLabel  = "Choose a name for your complex request. Make it filename compatible and unique within this workspace. After pressing <enter> program will open your $EDITOR and quit. You will then be able to write the contents of the request."
-	CreateComplexRequestLabel = "Choose a name for your request. Make it filename compatible and unique within this workspace. After pressing <enter> program will open your $EDITOR and quit. You will then be able to write the contents of the request."
+	CreateSimpleRequestLabel  = "Choose a name for your request. Make it filename compatible and unique within this workspace. After pressing <enter> program will open your $EDITOR. You will then be able to write the contents of the request."
+	CreateComplexRequestLabel = "Choose a name for your complex request. Make it filename compatible and unique within this workspace. After pressing <enter> program will open your $EDITOR. You will then be able to write the contents of the request."
 	RenameRequestLabel        = "Rename your request."
 	CopyRequestLabel          = "Choose name for your request."
+	PasteCurlRequestLabel     = "Choose a name for the request imported from the curl command in your clipboard."
 )
 
 const (
 	CreateSimpleRequest  = "CSmplReq"
 	CreateComplexRequest = "CCmplxReq"
-	EditRequest          = "EReq"
-	PrintRequest         = "PReq"
 	RenameRequest        = "RnReq"
 	CopyRequest          = "CpReq"
+	PasteCurlRequest     = "PCurlReq"
 )
 
 func modeStr(mode Mode) string {
@@ -73,9 +77,13 @@ type Request struct {
 	Url    string
 	Method string
 	Mold   model.RequestMold
+	Marked bool
 }
 
 func (i Request) Title() string {
+	if i.Marked {
+		return "● " + i.Name
+	}
 	return i.Name
 }
 
@@ -126,7 +134,7 @@ func updateStatusbar(m *uiModel, msg string) {
 		m.statusbar.FirstColumnColors.Background = statusbarModeEditBg
 		m.statusbar.ThirdColumnColors.Background = statusbarSecondColBg
 	} else {
-		profileText = "dev" // TODO get profile for realz
+		profileText = m.profile.Name
 		m.statusbar.FirstColumnColors.Background = statusbarModeSelectBg
 		m.statusbar.ThirdColumnColors.Background = statusbarThirdColBg
 	}
@@ -135,20 +143,42 @@ func updateStatusbar(m *uiModel, msg string) {
 }
 
 type uiModel struct {
-	mode       Mode
-	active     ActiveView
-	list       list.Model
-	preview    preview.Model
-	prompt     prompt.Model
-	stopwatch  stopwatch.Model
-	statusbar  statusbar.Model
-	width      int
-	height     int
-	postAction PostAction
+	profile  model.Profile
+	mode     Mode
+	active   ActiveView
+	list     list.Model
+	history  list.Model
+	preview  preview.Model
+	response preview.Model
+	prompt   prompt.Model
+	// panes of the split layout next to the list
+	source       preview.Model
+	lastResponse preview.Model
+	focus        Pane
+	panes        paneSizes
+	sourceKey    string
+	hasResponse  bool
+	stopwatch    stopwatch.Model
+	statusbar    statusbar.Model
+	width        int
+	height       int
+	// view to return to when preview is closed
+	previewParent ActiveView
+	// view to return to when response is closed
+	responseParent ActiveView
+	batch          batch
+	// changed request files of the workspace, nil when the workspace is not watched
+	requestChanges <-chan []loader.RequestChange
+	// cancels the running request
+	cancelRequest context.CancelFunc
+	// id of the active run, results of earlier runs that were cancelled or superseded are dropped
+	runId int
+	// history entry marked to be compared with the next one
+	diffMark *history.Entry
 }
 
 func (m uiModel) Init() tea.Cmd {
-	return nil
+	return waitForRequestChanges(m.requestChanges)
 }
 
 func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -158,28 +188,98 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.statusbar.SetSize(msg.Width)
 		updateStatusbar(&m, "")
-		m.list.SetWidth(msg.Width)
-		m.list.SetHeight(m.height - 2)
+		layoutPanes(&m)
+		m.history.SetSize(msg.Width, m.height-2)
+		m.response.Viewport.Width = msg.Width
+		m.response.Viewport.Height = msg.Height - m.response.VerticalMarginHeight()
 
 	case tea.KeyMsg:
 		// if we are filtering, it gets all the input
-		if m.list.FilterState() == list.Filtering {
+		if m.list.FilterState() == list.Filtering || (m.active == History && m.history.FilterState() == list.Filtering) {
 			break
 		}
 
+		if m.active == Batch {
+			return updateBatch(m, msg)
+		}
+
+		if m.active == List {
+			switch msg.String() {
+			case tea.KeyTab.String():
+				m.focus = m.focus.next()
+				return m, nil
+			case tea.KeyShiftTab.String():
+				m.focus = m.focus.prev()
+				return m, nil
+			}
+			// source and response panes only scroll
+			if m.focus != ListPane {
+				switch msg.String() {
+				case tea.KeyCtrlC.String(), "q":
+					return m, tea.Quit
+				case tea.KeyEsc.String():
+					m.focus = ListPane
+					return m, nil
+				case tea.KeyEnter.String(), "f":
+					if m.focus == ResponsePane && m.hasResponse {
+						m.active = Response
+						m.responseParent = List
+						return m, nil
+					}
+				}
+				return updateFocusedPane(m, msg)
+			}
+		}
+
 		switch keypress := msg.String(); keypress {
 		case tea.KeyCtrlC.String():
+			if m.cancelRequest != nil {
+				m.cancelRequest()
+			}
 			return m, tea.Quit
 		case "q":
 			if m.active == Preview {
+				m.active = m.previewParent
+				return m, nil
+			}
+			if m.active == History {
 				m.active = List
 				return m, nil
 			}
+			if m.active == Response {
+				return closeResponse(m)
+			}
 			if m.active == List {
 				return m, tea.Quit
 			}
 		case tea.KeyEsc.String():
-			if m.active == Preview || m.active == Prompt {
+			if m.active == Stopwatch {
+				if m.cancelRequest != nil {
+					m.cancelRequest()
+					m.cancelRequest = nil
+				}
+				m.runId++
+				m.active = m.responseParent
+				nowTime := time.Now().Format("15:04:05")
+				updateStatusbar(&m, fmt.Sprintf("%s Cancelled request", nowTime))
+				return m, nil
+			}
+			if m.active == Preview {
+				m.active = m.previewParent
+				return m, nil
+			}
+			if m.active == Prompt {
+				m.active = List
+				return m, nil
+			}
+			if m.active == Response {
+				return closeResponse(m)
+			}
+			if m.active == History {
+				// esc clears an applied filter first
+				if m.history.FilterState() == list.FilterApplied {
+					break
+				}
 				m.active = List
 				return m, nil
 			}
@@ -206,6 +306,14 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}, "", CreateComplexRequestLabel, checkRequestWithNameDoesNotExist(m), m.width)
 				return m, nil
 			}
+		case "v":
+			if m.mode == Edit && m.active == List {
+				return m, pasteCurlRequest()
+			}
+		case "h":
+			if m.mode == Select && m.active == List {
+				return m, loadHistory()
+			}
 		case "i":
 			if m.mode == Select && m.active == List {
 				m.mode = Edit
@@ -215,42 +323,141 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 	case RunRequestMsg:
-		m.active = Stopwatch
-		return m, tea.Batch(
-			m.stopwatch.Init(),
-			doRequest(msg.Request),
-		)
+		return startRun(m, List, func(ctx context.Context) tea.Cmd {
+			return doRequest(ctx, msg.Request, m.profile)
+		})
+	case RequestsChangedMsg:
+		if applied := applyRequestChanges(&m, msg.Changes); len(applied) > 0 {
+			syncSource(&m)
+			updateStatusbar(&m, reloadedStatus(applied))
+		}
+		return m, waitForRequestChanges(m.requestChanges)
+	case RunMarkedRequestsMsg:
+		if m.active == List {
+			if len(msg.Requests) == 0 {
+				nowTime := time.Now().Format("15:04:05")
+				updateStatusbar(&m, fmt.Sprintf("%s No marked requests, mark requests with <space>", nowTime))
+				return m, nil
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			m.batch = newBatch(msg.Requests, viper.GetInt("run.parallelism"))
+			m.batch.cancel = cancel
+			m.active = Batch
+			return m, startBatch(ctx, m.batch, m.profile)
+		}
+	case BatchRequestStartedMsg:
+		m.batch.rows[msg.Index].state = Running
+		m.batch.rows[msg.Index].started = time.Now()
+		return m, waitForBatchEvent(m.batch.events)
+	case BatchRequestFinishedMsg:
+		m.batch.rows[msg.Index].state = Finished
+		if msg.Finished.Canceled {
+			m.batch.rows[msg.Index].state = Canceled
+		}
+		m.batch.rows[msg.Index].finished = msg.Finished
+		return m, waitForBatchEvent(m.batch.events)
+	case BatchFinishedMsg:
+		m.batch.running = false
+		m.batch.cancel()
+		nowTime := time.Now().Format("15:04:05")
+		updateStatusbar(&m, fmt.Sprintf("%s Batch done, %s", nowTime, m.batch.summary()))
+		return m, nil
+	case BatchTickMsg:
+		if m.batch.running {
+			return m, batchTick()
+		}
+		return m, nil
+	case ShowHistoryMsg:
+		if m.active == List || m.active == History {
+			m.history = newHistoryList(msg.Entries, m.width, m.height-2)
+			m.diffMark = nil
+			m.active = History
+			return m, nil
+		}
+	case OpenHistoryEntryMsg:
+		if m.active == History {
+			m.active = Preview
+			m.previewParent = History
+			m.preview.Title = responseTitle(msg.Entry)
+			m.preview.Viewport.Width = m.width
+			m.preview.Viewport.Height = m.height - m.preview.VerticalMarginHeight()
+			m.preview.Viewport.SetContent(sprintHistoryEntry(msg.Entry))
+			m.preview.Viewport.GotoTop()
+			return m, nil
+		}
+	case DiffHistoryEntryMsg:
+		if m.active == History {
+			nowTime := time.Now().Format("15:04:05")
+			if msg.Entry.Response == nil {
+				updateStatusbar(&m, fmt.Sprintf("%s #%d has no response to compare", nowTime, msg.Entry.Id))
+				return m, nil
+			}
+			if m.diffMark == nil || m.diffMark.Id == msg.Entry.Id {
+				m.diffMark = &msg.Entry
+				updateStatusbar(&m, fmt.Sprintf("%s Marked #%d, press d on another entry to compare", nowTime, msg.Entry.Id))
+				return m, nil
+			}
+			a := *m.diffMark
+			m.diffMark = nil
+			result := diff.CompareResponses(a.Response, msg.Entry.Response, diff.ConfiguredOptions())
+			m.active = Preview
+			m.previewParent = History
+			m.preview.Title = fmt.Sprintf("Diff #%d ↔ #%d", a.Id, msg.Entry.Id)
+			m.preview.Viewport.Width = m.width
+			m.preview.Viewport.Height = m.height - m.preview.VerticalMarginHeight()
+			m.preview.Viewport.SetContent(print.SprintPrettyDiff(result, a.Label(), msg.Entry.Label()))
+			m.preview.Viewport.GotoTop()
+			return m, nil
+		}
+	case RerunHistoryEntryMsg:
+		if m.active == History {
+			return startRun(m, History, func(ctx context.Context) tea.Cmd {
+				return rerunHistoryEntry(ctx, msg.Entry, m.list.Items(), m.profile)
+			})
+		}
 	case RequestFinishedMsg:
-		m.postAction = PostAction{
-			Type:    PrintRequest,
-			Payload: string(msg),
+		// a cancelled request has already returned to the previous view, a superseded one must not replace the
+		// response of the active run
+		if msg.Canceled || msg.RunId != m.runId {
+			return m, nil
 		}
-		return m, tea.Quit
-	case EditRequestMsg:
-		m.postAction = PostAction{
-			Type:    EditRequest,
-			Payload: msg.Request,
+		if m.cancelRequest != nil {
+			m.cancelRequest()
+			m.cancelRequest = nil
 		}
-		return m, tea.Quit
+		title := responseTitle(msg.Entry)
+		content := sprintHistoryEntry(msg.Entry)
+		m.response.Title = title
+		m.response.Viewport.SetContent(content)
+		m.response.Viewport.GotoTop()
+		m.lastResponse.Title = title
+		m.lastResponse.Viewport.SetContent(content)
+		m.lastResponse.Viewport.GotoTop()
+		m.hasResponse = true
+		if m.responseParent == List {
+			m.active = List
+			nowTime := time.Now().Format("15:04:05")
+			updateStatusbar(&m, fmt.Sprintf("%s Finished %s", nowTime, title))
+			return m, nil
+		}
+		m.active = Response
+		return m, nil
+	case ExportRequestMsg:
+		return m, exportRequestToClipboard(msg.Request, m.profile)
+	case EditRequestMsg:
+		return m, openFileToEditor(msg.Request.Mold.Root, msg.Request.Mold.Filename, false)
+	case EditorFinishedMsg:
+		updateStatusbar(&m, refreshEditedRequest(&m, msg))
+		syncSource(&m)
+		return m, nil
 	case PreviewRequestMsg:
 		if m.active == List {
 			m.active = Preview
+			m.previewParent = List
+			m.preview.Title = "Request"
 			m.preview.Viewport.Width = m.width
 			m.preview.Viewport.Height = m.height - m.preview.VerticalMarginHeight()
-			selected := msg.Request
-			var formatted string
-			var err error
-			switch selected.Mold.ContentType {
-			case "yaml":
-				formatted, err = print.SprintYaml(selected.Mold.Raw())
-			case "star":
-				formatted, err = print.SprintStar(selected.Mold.Raw())
-			}
-
-			if formatted == "" || err != nil {
-				formatted = selected.Mold.Raw()
-			}
-			m.preview.Viewport.SetContent(formatted)
+			m.preview.Viewport.SetContent(sprintSource(msg.Request))
 			m.preview.Viewport.YPosition = 0
 			return m, nil
 		}
@@ -271,13 +478,22 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Additional: msg.Request,
 			}, fmt.Sprintf("Copy of %s", msg.Request.Name), CopyRequestLabel, checkRequestWithNameDoesNotExist(m), m.width)
 		}
+	case PasteCurlRequestMsg:
+		if m.active == List {
+			m.active = Prompt
+			m.prompt = prompt.New(prompt.PromptContext{
+				Key:        PasteCurlRequest,
+				Additional: msg.Request,
+			}, importer.SuggestName(msg.Request), PasteCurlRequestLabel, checkRequestWithNameDoesNotExist(m), m.width)
+			return m, nil
+		}
 	case prompt.PromptAnsweredMsg:
 		if msg.Context.Key == RenameRequest {
 			m.active = List
-			renamedRequest, ok := renameRequest(msg.Input, msg.Context.Additional.(Request))
+			original := msg.Context.Additional.(Request)
+			renamedRequest, ok := renameRequest(msg.Input, original)
 			if ok {
-				log.Debug().Msgf("Index of renamed item is %d", m.list.Index())
-				setCmd := m.list.SetItem(m.list.Index(), renamedRequest)
+				setCmd := setRequest(&m.list, original.Mold.Filename, renamedRequest)
 				statusCmd := tea.Cmd(func() tea.Msg {
 					nowTime := time.Now().Format("15:04:05")
 					return StatusMessage(fmt.Sprintf("%s Renamed request to %s", nowTime, renamedRequest.Title()))
@@ -292,9 +508,10 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		} else if msg.Context.Key == CopyRequest {
 			m.active = List
-			copiedRequest, ok := copyRequest(msg.Input, msg.Context.Additional.(Request))
+			original := msg.Context.Additional.(Request)
+			copiedRequest, ok := copyRequest(msg.Input, original)
 			if ok {
-				setCmd := m.list.InsertItem(m.list.Index()+1, copiedRequest)
+				setCmd := m.list.InsertItem(requestIndex(&m.list, original.Mold.Filename)+1, copiedRequest)
 				statusCmd := tea.Cmd(func() tea.Msg {
 					nowTime := time.Now().Format("15:04:05")
 					return StatusMessage(fmt.Sprintf("%s Copied request to %s", nowTime, copiedRequest.Title()))
@@ -308,13 +525,29 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, statusCmd
 			}
 
-		} else {
-			m.postAction = PostAction{
-				Type:             msg.Context.Key,
-				Payload:          msg.Input,
-				AddtionalContext: msg.Context.Additional,
+		} else if msg.Context.Key == PasteCurlRequest {
+			m.active = List
+			importedRequest, ok := saveCurlRequest(msg.Input, msg.Context.Additional.(*model.YamlRequest), m.profile)
+			if ok {
+				setCmd := m.list.InsertItem(selectedRequestIndex(&m.list)+1, importedRequest)
+				statusCmd := tea.Cmd(func() tea.Msg {
+					nowTime := time.Now().Format("15:04:05")
+					return StatusMessage(fmt.Sprintf("%s Imported request %s", nowTime, importedRequest.Title()))
+				})
+				return m, tea.Batch(setCmd, statusCmd)
+			} else {
+				statusCmd := tea.Cmd(func() tea.Msg {
+					nowTime := time.Now().Format("15:04:05")
+					return StatusMessage(fmt.Sprintf("%s Failed to import request", nowTime))
+				})
+				return m, statusCmd
 			}
-			return m, tea.Quit
+		} else if msg.Context.Key == CreateSimpleRequest {
+			m.active = List
+			return m, createSimpleRequestFile(msg.Input)
+		} else if msg.Context.Key == CreateComplexRequest {
+			m.active = List
+			return m, createComplexRequestFile(msg.Input)
 		}
 
 	case StatusMessage:
@@ -326,10 +559,15 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.active {
 	case List:
 		m.list, cmd = m.list.Update(msg)
+		syncSource(&m)
 	case Prompt:
 		m.prompt, cmd = m.prompt.Update(msg)
 	case Preview:
 		m.preview, cmd = m.preview.Update(msg)
+	case History:
+		m.history, cmd = m.history.Update(msg)
+	case Response:
+		m.response, cmd = m.response.Update(msg)
 	case Stopwatch:
 		m.stopwatch, cmd = m.stopwatch.Update(msg)
 	}
@@ -339,22 +577,71 @@ func (m uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m uiModel) View() string {
 	switch m.active {
 	case List:
-		return renderList(m)
+		return renderPanes(m)
 	case Prompt:
 		return renderPrompt(m)
 	case Preview:
 		return m.preview.View()
+	case History:
+		return renderHistory(m)
+	case Response:
+		return m.response.View()
+	case Batch:
+		return renderBatch(m)
 	case Stopwatch:
-		return stopwatchStyle.Render("Running request... :: Elapsed time: " + m.stopwatch.View())
+		if m.responseParent == List {
+			return renderPanes(m)
+		}
+		return stopwatchStyle.Render("Running request... :: Elapsed time: " + m.stopwatch.View() + " :: esc to cancel")
 	default:
-		return renderList(m)
+		return renderPanes(m)
+	}
+}
+
+// startRun runs the request of run as a new run, its response is shown over parent. A previous run is cancelled and
+// its result dropped when it arrives.
+func startRun(m uiModel, parent ActiveView, run func(ctx context.Context) tea.Cmd) (tea.Model, tea.Cmd) {
+	if m.cancelRequest != nil {
+		m.cancelRequest()
 	}
+	m.runId++
+	m.active = Stopwatch
+	m.responseParent = parent
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelRequest = cancel
+
+	runId := m.runId
+	request := run(ctx)
+	return m, tea.Batch(
+		m.stopwatch.Reset(),
+		m.stopwatch.Start(),
+		func() tea.Msg {
+			msg := request()
+			if finished, ok := msg.(RequestFinishedMsg); ok {
+				finished.RunId = runId
+				return finished
+			}
+			return msg
+		},
+	)
 }
 
-func renderList(m uiModel) string {
+// closeResponse returns to the view the request was run from, history is reloaded to include the new run
+func closeResponse(m uiModel) (tea.Model, tea.Cmd) {
+	m.active = m.responseParent
+	if m.active == Batch {
+		return m, nil
+	}
+	if m.active == History {
+		return m, loadHistory()
+	}
+	return m, nil
+}
+
+func renderHistory(m uiModel) string {
 	return lipgloss.JoinVertical(
 		lipgloss.Top,
-		lipgloss.NewStyle().Height(m.height-statusbar.Height).Render(m.list.View()),
+		lipgloss.NewStyle().Height(m.height-statusbar.Height).Render(m.history.View()),
 		m.statusbar.View(),
 	)
 }
@@ -368,19 +655,23 @@ func renderPrompt(m uiModel) string {
 		m.prompt.View())
 }
 
-func Start(loadedRequests []model.RequestMold) {
-	log.Info().Msgf("Starting up manage TUI with %d loaded requests", len(loadedRequests))
+// newRequest lists a request mold with the url it is sent to with the profile
+func newRequest(mold model.RequestMold, profile model.Profile) Request {
+	return Request{
+		Name:   mold.Name(),
+		Url:    builder.PreviewUrl(mold, profile),
+		Method: mold.Method(),
+		Mold:   mold,
+	}
+}
+
+func Start(loadedRequests []model.RequestMold, profile model.Profile) {
+	log.Info().Msgf("Starting up manage TUI with %d loaded requests and profile %s", len(loadedRequests), profile.Name)
 
 	var requests []list.Item
 
 	for _, v := range loadedRequests {
-		r := Request{
-			Name:   v.Name(),
-			Url:    v.Url(),
-			Method: v.Method(),
-			Mold:   v,
-		}
-		requests = append(requests, r)
+		requests = append(requests, newRequest(v, profile))
 	}
 
 	var d list.DefaultDelegate
@@ -417,17 +708,25 @@ func Start(loadedRequests []model.RequestMold) {
 			Background: statusbarFourthColBg,
 		},
 	)
-	m := uiModel{list: requestList, active: List, mode: Select, stopwatch: stopwatch.NewWithInterval(time.Millisecond), statusbar: sb}
+	m := uiModel{profile: profile, list: requestList, history: newHistoryList(nil, 0, 0), preview: preview.New(), response: preview.New(), source: preview.New(), lastResponse: preview.New(), active: List, mode: Select, stopwatch: stopwatch.NewWithInterval(time.Millisecond), statusbar: sb}
+
+	m.lastResponse.Title = "Response"
+	m.lastResponse.Viewport.SetContent("Run a request with <enter> to see its response here.")
+	syncSource(&m)
+
+	watcher, err := loader.WatchRequests(viper.GetString("workspace"))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to watch workspace, the request list will not be reloaded")
+	} else {
+		defer watcher.Close()
+		m.requestChanges = watcher.Changes
+	}
 
 	p := tea.NewProgram(m, tea.WithAltScreen())
 
-	r, err := p.Run()
+	_, err = p.Run()
 	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
-
-	if m, ok := r.(uiModel); ok {
-		handlePostAction(m)
-	}
 }
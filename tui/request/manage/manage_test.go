@@ -0,0 +1,105 @@
+package managetui
+
+import (
+	"goful/core/history"
+	"goful/core/model"
+	"goful/core/redact"
+	preview "goful/tui/request/preview"
+	prompt "goful/tui/request/prompt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/stopwatch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestModel() uiModel {
+	return uiModel{
+		history:      newHistoryList(nil, 80, 20),
+		response:     preview.New(),
+		lastResponse: preview.New(),
+		active:       History,
+		stopwatch:    stopwatch.New(),
+	}
+}
+
+func TestRequestFinishedFromSupersededRun(t *testing.T) {
+	m := newTestModel()
+
+	// the first run is cancelled with esc and a second one started before the result of the first arrives
+	next, _ := m.Update(RerunHistoryEntryMsg{Entry: history.Entry{Name: "first"}})
+	first := next.(uiModel).runId
+	next, _ = next.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	next, _ = next.Update(RerunHistoryEntryMsg{Entry: history.Entry{Name: "second"}})
+
+	next, _ = next.Update(RequestFinishedMsg{Entry: history.Entry{Name: "first"}, RunId: first})
+	m = next.(uiModel)
+	if m.active != Stopwatch || m.hasResponse {
+		t.Errorf("expected the result of the superseded run to be dropped, got view %v", m.active)
+	}
+
+	next, _ = m.Update(RequestFinishedMsg{Entry: history.Entry{Name: "second"}, RunId: m.runId})
+	m = next.(uiModel)
+	if m.active != Response || m.response.Title != responseTitle(history.Entry{Name: "second"}) {
+		t.Errorf("expected the response of the active run, got view %v titled %s", m.active, m.response.Title)
+	}
+}
+
+func TestRerunOutsideOfHistory(t *testing.T) {
+	m := newTestModel()
+	m.active = Response
+
+	next, _ := m.Update(RerunHistoryEntryMsg{Entry: history.Entry{Name: "first"}})
+	m = next.(uiModel)
+	if m.active != Response || m.runId != 0 || m.cancelRequest != nil {
+		t.Errorf("expected the rerun to be ignored outside of the history, got view %v and run %d", m.active, m.runId)
+	}
+}
+
+func TestCopyWithFilterActive(t *testing.T) {
+	root := t.TempDir()
+	var items []list.Item
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		r := newTestRequest(name)
+		r.Mold.Root = root
+		items = append(items, r)
+	}
+	m := newTestModel()
+	m.list = list.New(items, newSelectDelegate(), 80, 20)
+	m.list = update(m.list, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	m.list = update(m.list, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("gamma")})
+	m.list = update(m.list, tea.KeyMsg{Type: tea.KeyEnter})
+
+	original := m.list.SelectedItem().(Request)
+	next, _ := m.Update(prompt.PromptAnsweredMsg{
+		Context: prompt.PromptContext{Key: CopyRequest, Additional: original},
+		Input:   "gamma copy",
+	})
+	m = next.(uiModel)
+
+	var names []string
+	for _, item := range m.list.Items() {
+		names = append(names, item.(Request).Name)
+	}
+	// the copy is placed after the original among all items, not after the position of the original in the filter
+	wanted := []string{"alpha", "beta", "gamma", "gamma copy"}
+	if !cmp.Equal(names, wanted) {
+		t.Errorf("got %v, wanted %v", names, wanted)
+	}
+}
+
+func TestSprintSourceRedactsVariables(t *testing.T) {
+	redact.SetVariables(map[string]string{"api_token": "abcdef123456"})
+	t.Cleanup(func() { redact.SetVariables() })
+
+	r := Request{Mold: model.RequestMold{
+		ContentType: "yaml",
+		Yaml:        &model.YamlRequest{Raw: "url: https://foobar.com\nheaders:\n  X-Token: [abcdef123456]\n"},
+	}}
+	got := sprintSource(r)
+	if strings.Contains(got, "abcdef123456") || !strings.Contains(got, redact.Mask) {
+		t.Errorf("expected the value of api_token to be redacted, got\n%s", got)
+	}
+}
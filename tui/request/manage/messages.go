@@ -1,18 +1,39 @@
 package managetui
 
+import (
+	"goful/core/history"
+	"goful/core/loader"
+	"goful/core/model"
+)
+
 type RunRequestMsg struct {
 	Request Request
 }
 
+type ExportRequestMsg struct {
+	Request Request
+}
+
 type EditRequestMsg struct {
 	Request Request
 }
 
+type EditorFinishedMsg struct {
+	Filename string
+	Created  bool
+	Err      error
+}
+
 type PreviewRequestMsg struct {
 	Request Request
 }
 
-type RequestFinishedMsg string
+type RequestFinishedMsg struct {
+	Entry    history.Entry
+	Canceled bool
+	// RunId is the run the request was started by, see startRun
+	RunId int
+}
 
 type StatusMessage string
 
@@ -23,3 +44,44 @@ type RenameRequestMsg struct {
 type CopyRequestMsg struct {
 	Request Request
 }
+
+type PasteCurlRequestMsg struct {
+	Request *model.YamlRequest
+}
+
+type ShowHistoryMsg struct {
+	Entries []history.Entry
+}
+
+type OpenHistoryEntryMsg struct {
+	Entry history.Entry
+}
+
+type RerunHistoryEntryMsg struct {
+	Entry history.Entry
+}
+
+type DiffHistoryEntryMsg struct {
+	Entry history.Entry
+}
+
+type RequestsChangedMsg struct {
+	Changes []loader.RequestChange
+}
+
+type RunMarkedRequestsMsg struct {
+	Requests []Request
+}
+
+type BatchRequestStartedMsg struct {
+	Index int
+}
+
+type BatchRequestFinishedMsg struct {
+	Index    int
+	Finished RequestFinishedMsg
+}
+
+type BatchFinishedMsg struct{}
+
+type BatchTickMsg struct{}
@@ -0,0 +1,96 @@
+package managetui
+
+import (
+	"fmt"
+	"goful/core/loader"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func waitForRequestChanges(changes <-chan []loader.RequestChange) tea.Cmd {
+	if changes == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		c, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return RequestsChangedMsg{Changes: c}
+	}
+}
+
+// applyRequestChanges updates the changed requests of the list in place and returns the changes that did alter the
+// list. The filter is kept and the selected request stays selected unless it was removed.
+func applyRequestChanges(m *uiModel, changes []loader.RequestChange) []loader.RequestChange {
+	selected, hasSelection := m.list.SelectedItem().(Request)
+	selectedIndex := m.list.Index()
+
+	items := slices.Clone(m.list.Items())
+	var applied []loader.RequestChange
+	for _, change := range changes {
+		index := slices.IndexFunc(items, func(item list.Item) bool {
+			r, ok := item.(Request)
+			return ok && r.Mold.Filename == change.Filename
+		})
+		switch {
+		case change.Mold == nil && index >= 0:
+			items = slices.Delete(items, index, index+1)
+		case change.Mold == nil:
+			continue
+		case index >= 0:
+			existing := items[index].(Request)
+			// changes made from the ui itself are already in the list
+			if existing.Mold.Raw() == change.Mold.Raw() {
+				continue
+			}
+			request := newRequest(*change.Mold, m.profile)
+			request.Marked = existing.Marked
+			items[index] = request
+		default:
+			// new files are placed in filename order like ReadRequests lists them
+			insertAt := slices.IndexFunc(items, func(item list.Item) bool {
+				r, ok := item.(Request)
+				return ok && r.Mold.Filename > change.Filename
+			})
+			if insertAt < 0 {
+				insertAt = len(items)
+			}
+			items = slices.Insert(items, insertAt, list.Item(newRequest(*change.Mold, m.profile)))
+		}
+		applied = append(applied, change)
+	}
+	if len(applied) == 0 {
+		return nil
+	}
+
+	if cmd := m.list.SetItems(items); cmd != nil {
+		// filter right away so that the selection can be restored among the filtered items
+		m.list, _ = m.list.Update(cmd())
+	}
+
+	visible := m.list.VisibleItems()
+	index := -1
+	if hasSelection {
+		index = slices.IndexFunc(visible, func(item list.Item) bool {
+			r, ok := item.(Request)
+			return ok && r.Mold.Filename == selected.Mold.Filename
+		})
+	}
+	if index < 0 {
+		index = min(selectedIndex, len(visible)-1)
+	}
+	m.list.Select(max(0, index))
+	return applied
+}
+
+func reloadedStatus(changes []loader.RequestChange) string {
+	nowTime := time.Now().Format("15:04:05")
+	if len(changes) == 1 {
+		return fmt.Sprintf("%s Reloaded %s", nowTime, changes[0].Filename)
+	}
+	return fmt.Sprintf("%s Reloaded %d changed files", nowTime, len(changes))
+}
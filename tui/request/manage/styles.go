@@ -34,6 +34,17 @@ var (
 	statusbarFourthColBg  = lipgloss.AdaptiveColor{Light: "#89b4fa", Dark: "#89b4fa"}
 	statusbarFourthColFg  = lipgloss.AdaptiveColor{Light: "#1e1e2e", Dark: "#1e1e2e"}
 
+	batchPendingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6c7086"))
+	batchRunningStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f9e2af"))
+	batchOkStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#a6e3a1"))
+	batchFailedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#f38ba8"))
+
+	historyStatusOkColor    = lipgloss.Color("#a6e3a1")
+	historyStatusErrorColor = lipgloss.Color("#f38ba8")
+
+	paneStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#45475a"))
+	focusedPaneStyle = paneStyle.Copy().BorderForeground(lipgloss.Color("#89b4fa"))
+
 	listStyle    = lipgloss.NewStyle().BorderBackground(lipgloss.Color("#cdd6f4"))
 	methodColors = map[string]string{
 		"GET":    "#89b4fa",
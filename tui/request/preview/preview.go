@@ -30,6 +30,7 @@ var (
 
 type Model struct {
 	Viewport viewport.Model
+	Title    string
 }
 
 func (m Model) Init() tea.Cmd {
@@ -80,7 +81,7 @@ func (m Model) VerticalMarginHeight() int {
 }
 
 func (m Model) headerView() string {
-	title := titleStyle.Render("Request")
+	title := titleStyle.Render(m.Title)
 	line := strings.Repeat("─", max(0, m.Viewport.Width-lipgloss.Width(title)))
 	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
 }
@@ -108,6 +109,7 @@ func New() Model {
 
 	m := Model{
 		Viewport: viewport.New(0, 0),
+		Title:    "Request",
 	}
 
 	return m
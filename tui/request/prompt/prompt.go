@@ -39,6 +39,8 @@ var keys = keyMap{
 }
 
 type Model struct {
+	// Heading is shown above the label, defaults to "Name"
+	Heading   string
 	nameInput textinput.Model
 	context   PromptContext
 	label     string
@@ -82,7 +84,7 @@ func (m Model) View() string {
 	helpView := m.help.View(m.keys)
 
 	inputViews := []string{}
-	inputViews = append(inputViews, "Name")
+	inputViews = append(inputViews, m.Heading)
 	var descStyle = descriptionStyle.Width(m.nameInput.Width)
 	inputViews = append(inputViews, descStyle.Render(m.label))
 
@@ -111,6 +113,7 @@ func New(context PromptContext, initialValue string, label string, validator fun
 	}
 
 	return Model{
+		Heading:   "Name",
 		context:   context,
 		nameInput: nameInput,
 		label:     label,
@@ -119,6 +122,11 @@ func New(context PromptContext, initialValue string, label string, validator fun
 	}
 }
 
+// SetCharLimit changes the maximum length of the input, names are limited to 32 characters by default
+func (m *Model) SetCharLimit(limit int) {
+	m.nameInput.CharLimit = limit
+}
+
 type PromptContext struct {
 	Key        string
 	Additional interface{}